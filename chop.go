@@ -4,10 +4,103 @@ import (
 	"github.com/fogleman/fauxgl"
 )
 
+type Options struct {
+	// adds pegs to the front cap and matching sockets to the back cap
+	Pegs *PegOptions
+
+	// replaces the flat caps with a joint; cannot be combined with pegs or
+	// a kerf
+	Joint *JointOptions
+
+	// removes a slab of this thickness between the front and back parts,
+	// centered KerfOffset in front of the plane
+	Kerf       float64
+	KerfOffset float64
+
+	// distance below which intersections snap to mesh vertices; 0 derives it
+	// from the size of the mesh
+	Tolerance float64
+
+	// vertex color of the caps, pegs and sockets
+	CapColor fauxgl.Color
+}
+
+type Part struct {
+	Mesh *fauxgl.Mesh
+
+	// planes the part was cut off by, facing into it; with a kerf they are
+	// the sides of the kerf
+	Planes []Plane
+}
+
 func Chop(mesh *fauxgl.Mesh, point, normal fauxgl.Vector) *fauxgl.Mesh {
-	plane := MakePlane(point, normal)
-	clipped := plane.ClipMesh(mesh)
-	sliced := plane.SliceMesh(mesh)
-	clipped.Add(sliced)
-	return clipped
+	return MakePlane(point, normal).Chop(mesh)
+}
+
+func ChopIndexed(mesh *fauxgl.Mesh, point, normal fauxgl.Vector) *HalfEdgeMesh {
+	return MakePlane(point, normal).ChopIndexed(mesh)
+}
+
+func ChopReport(mesh *fauxgl.Mesh, point, normal fauxgl.Vector) (*fauxgl.Mesh, *Report) {
+	return MakePlane(point, normal).ChopReport(mesh)
+}
+
+func ChopBoth(mesh *fauxgl.Mesh, point, normal fauxgl.Vector) (front, back *fauxgl.Mesh) {
+	return MakePlane(point, normal).ChopBoth(mesh)
+}
+
+func ChopBothReport(mesh *fauxgl.Mesh, point, normal fauxgl.Vector) (front, back *fauxgl.Mesh, report *Report) {
+	return MakePlane(point, normal).ChopBothReport(mesh)
+}
+
+func ChopWith(mesh *fauxgl.Mesh, point, normal fauxgl.Vector, options Options) (front, back *fauxgl.Mesh, err error) {
+	return MakePlane(point, normal).ChopWith(mesh, options)
+}
+
+func ChopWithReport(mesh *fauxgl.Mesh, point, normal fauxgl.Vector, options Options) (front, back *fauxgl.Mesh, report *Report, err error) {
+	return MakePlane(point, normal).ChopWithReport(mesh, options)
+}
+
+func ChopMany(mesh *fauxgl.Mesh, planes []Plane) []*Part {
+	parts, _ := ChopManyWith(mesh, planes, Options{})
+	return parts
+}
+
+// ChopMany cutting every part with the options; the tolerance, unless set
+// in the options, comes from the whole mesh so that the parts snap alike
+func ChopManyWith(mesh *fauxgl.Mesh, planes []Plane, options Options) ([]*Part, error) {
+	if options.Tolerance <= 0 {
+		options.Tolerance = defaultTolerance(mesh)
+	}
+	parts := []*Part{{mesh, nil}}
+	for _, plane := range planes {
+		var next []*Part
+		for _, part := range parts {
+			if !plane.cuts(part.Mesh) {
+				next = append(next, part)
+				continue
+			}
+			front, back, err := plane.ChopWith(part.Mesh, options)
+			if err != nil {
+				return nil, err
+			}
+			if len(front.Triangles) == 0 || len(back.Triangles) == 0 {
+				// plane does not cut this part
+				next = append(next, part)
+				continue
+			}
+			fp, bp := plane.kerfPlanes(options)
+			next = append(next, part.split(front, fp))
+			next = append(next, part.split(back, bp.Negate()))
+		}
+		parts = next
+	}
+	return parts, nil
+}
+
+func (part *Part) split(mesh *fauxgl.Mesh, plane Plane) *Part {
+	planes := make([]Plane, len(part.Planes)+1)
+	copy(planes, part.Planes)
+	planes[len(part.Planes)] = plane
+	return &Part{mesh, planes}
 }
@@ -1,6 +1,7 @@
 package chopsui
 
 import (
+	"fmt"
 	"math"
 
 	"github.com/fogleman/fauxgl"
@@ -29,10 +30,12 @@ type AppInteractor struct {
 	PlaneInteractor *Arcball
 	Modifiers       glfw.ModifierKey
 	Callback        func(*AppInteractor)
+	Repair          bool
+	Split           bool
 }
 
 func NewAppInteractor(callback func(*AppInteractor)) *AppInteractor {
-	return &AppInteractor{NewArcball(), NewArcball(), 0, callback}
+	return &AppInteractor{NewArcball(), NewArcball(), 0, callback, false, false}
 }
 
 func (a *AppInteractor) ForMesh(mods glfw.ModifierKey) bool {
@@ -70,6 +73,16 @@ func (a *AppInteractor) KeyCallback(window *glfw.Window, key glfw.Key, scancode
 		a.Chop()
 		return
 	}
+	if action == glfw.Press && key == glfw.KeyR {
+		a.Repair = !a.Repair
+		fmt.Printf("repair before chop: %v\n", a.Repair)
+		return
+	}
+	if action == glfw.Press && key == glfw.KeyS {
+		a.Split = !a.Split
+		fmt.Printf("split chopped halves: %v\n", a.Split)
+		return
+	}
 	a.Modifiers = mods
 	if a.ForMesh(a.Modifiers) {
 		a.MeshInteractor.KeyCallback(window, key, scancode, action, mods)
@@ -8,6 +8,9 @@ import (
 type MeshData struct {
 	Buffer []float32
 	Box    fauxgl.Box
+
+	// file the mesh was loaded from, read again for vertex attributes
+	Path string
 }
 
 type Mesh struct {
@@ -48,7 +51,7 @@ func NewPlaneMesh() *Mesh {
 		-n, n, 0,
 	}
 	box := fauxgl.Box{fauxgl.Vector{-n, -n, 0}, fauxgl.Vector{n, n, 0}}
-	mesh := NewMesh(&MeshData{buffer, box})
+	mesh := NewMesh(&MeshData{buffer, box, ""})
 	mesh.Transform = fauxgl.Identity()
 	return mesh
 }
@@ -66,7 +69,14 @@ func (mesh *Mesh) Destroy() {
 	gl.DeleteBuffers(1, &mesh.VertexBuffer)
 }
 
+// mesh with the normals, texture coordinates and colors of its file, which
+// the position buffer leaves out, or just positions if the file has none
 func (mesh *Mesh) ToFauxgl() *fauxgl.Mesh {
+	if isOBJ(mesh.Data.Path) {
+		if m, err := fauxgl.LoadOBJ(mesh.Data.Path); err == nil {
+			return m
+		}
+	}
 	b := mesh.Data.Buffer
 	nv := len(b) / 3
 	nt := nv / 3
@@ -145,19 +145,38 @@ func Run(path string) {
 		}
 		start := time.Now()
 		fm := mesh.ToFauxgl()
+		if a.Repair {
+			var report *choppy.RepairReport
+			fm, report = choppy.Repair(fm, choppy.RepairOptions{})
+			fmt.Println(report)
+		}
 		m1 := a.MeshInteractor.matrix().Mul(mesh.Transform)
 		m2 := a.PlaneInteractor.matrix().Mul(planeMesh.Transform)
 		fm.Transform(m1)
 		point := m2.MulPosition(fauxgl.Vector{})
 		normal := m2.MulDirection(fauxgl.Vector{0, 0, 1})
-		fm1 := choppy.Chop(fm, point, normal)
-		fm2 := choppy.Chop(fm, point, normal.Negate())
+		fm1, fm2 := choppy.ChopBoth(fm, point, normal)
 		fm1.Transform(m1.Inverse())
 		fm2.Transform(m1.Inverse())
 		fmt.Printf(
 			"chopped mesh in %.3f seconds\n", time.Since(start).Seconds())
-		fm1.SaveSTL("out1.stl")
-		fm2.SaveSTL("out2.stl")
+		ext := ".stl"
+		if isOBJ(mesh.Data.Path) {
+			ext = ".obj"
+		}
+		for i, half := range []*fauxgl.Mesh{fm1, fm2} {
+			name := fmt.Sprintf("out%d", i+1)
+			if !a.Split {
+				saveMesh(name+ext, half)
+				continue
+			}
+			for j, piece := range choppy.Components(half) {
+				path := fmt.Sprintf("%s-%d%s", name, j+1, ext)
+				box := piece.BoundingBox()
+				fmt.Printf("%s: volume %g, bounding box %v to %v\n", path, piece.Volume(), box.Min, box.Max)
+				saveMesh(path, piece)
+			}
+		}
 	}
 
 	// create interactor
@@ -85,7 +85,7 @@ func loadSTLA(file *os.File) (*MeshData, error) {
 		i++
 	}
 	box := boxForData(data)
-	return &MeshData{data, box}, scanner.Err()
+	return &MeshData{data, box, ""}, scanner.Err()
 }
 
 func makeFloat(b []byte) float32 {
@@ -136,5 +136,5 @@ func loadSTLB(file *os.File, count int) (*MeshData, error) {
 	wg.Wait()
 
 	box := boxForData(data)
-	return &MeshData{data, box}, nil
+	return &MeshData{data, box, ""}, nil
 }
@@ -5,18 +5,37 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/fogleman/choppy"
 	"github.com/fogleman/fauxgl"
 )
 
 func LoadMesh(path string) (*MeshData, error) {
+	var data *MeshData
+	var err error
 	ext := strings.ToLower(filepath.Ext(path))
 	switch ext {
 	case ".stl":
-		return LoadSTL(path)
+		data, err = LoadSTL(path)
 	case ".obj":
-		return LoadOBJ(path)
+		data, err = LoadOBJ(path)
+	default:
+		return nil, fmt.Errorf("unrecognized mesh extension: %s", ext)
 	}
-	return nil, fmt.Errorf("unrecognized mesh extension: %s", ext)
+	if data != nil {
+		data.Path = path
+	}
+	return data, err
+}
+
+func isOBJ(path string) bool {
+	return strings.ToLower(filepath.Ext(path)) == ".obj"
+}
+
+func saveMesh(path string, mesh *fauxgl.Mesh) error {
+	if isOBJ(path) {
+		return choppy.SaveOBJ(path, mesh)
+	}
+	return mesh.SaveSTL(path)
 }
 
 func boxForData(data []float32) fauxgl.Box {
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"log"
+
+	"github.com/fogleman/choppy"
+	"github.com/fogleman/fauxgl"
+	kingpin "gopkg.in/alecthomas/kingpin.v2"
+)
+
+var (
+	booleanCommand = kingpin.Command("boolean", "Split a mesh by a closed cutter mesh.")
+	booleanInput   = booleanCommand.Flag("input", "Input STL file.").Short('i').Required().ExistingFile()
+	booleanCutter  = booleanCommand.Flag("cutter", "Cutter STL file.").Short('c').Required().ExistingFile()
+	booleanInside  = booleanCommand.Flag("inside", "Output STL or OBJ file for the part inside the cutter.").Required().String()
+	booleanOutside = booleanCommand.Flag("outside", "Output STL or OBJ file for the part outside the cutter.").Required().String()
+)
+
+func boolean() {
+	mesh, err := fauxgl.LoadMesh(*booleanInput)
+	if err != nil {
+		log.Fatal(err)
+	}
+	cutter, err := fauxgl.LoadMesh(*booleanCutter)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	inside, outside, err := choppy.ChopWithMesh(mesh, cutter)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("inside: %d triangles, volume %g", len(inside.Triangles), inside.Volume())
+	log.Printf("outside: %d triangles, volume %g", len(outside.Triangles), outside.Volume())
+	if err := saveMesh(*booleanInside, inside); err != nil {
+		log.Fatal(err)
+	}
+	if err := saveMesh(*booleanOutside, outside); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"log"
+
+	"github.com/fogleman/choppy"
+	"github.com/fogleman/fauxgl"
+	kingpin "gopkg.in/alecthomas/kingpin.v2"
+)
+
+var (
+	clipCommand = kingpin.Command("clip", "Keep the part of a mesh inside a box, capped.")
+	clipInput   = clipCommand.Flag("input", "Input STL file.").Short('i').Required().ExistingFile()
+	clipOutput  = clipCommand.Flag("output", "Output STL or OBJ file.").Short('o').Required().String()
+	clipMin     = clipCommand.Flag("min", "Minimum corner of the box, as X,Y,Z.").Required().String()
+	clipMax     = clipCommand.Flag("max", "Maximum corner of the box, as X,Y,Z.").Required().String()
+)
+
+func clip() {
+	min, err := parseVector(*clipMin)
+	if err != nil {
+		log.Fatal(err)
+	}
+	max, err := parseVector(*clipMax)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	mesh, err := fauxgl.LoadMesh(*clipInput)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	mesh = choppy.ClipBox(mesh, fauxgl.Box{min, max})
+	log.Printf("%d triangles, volume %g", len(mesh.Triangles), mesh.Volume())
+	if err := saveMesh(*clipOutput, mesh); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/fogleman/choppy"
+	"github.com/fogleman/fauxgl"
+	kingpin "gopkg.in/alecthomas/kingpin.v2"
+)
+
+var (
+	fitCommand = kingpin.Command("fit", "Split a mesh into parts that fit a build volume.")
+	fitInput   = fitCommand.Flag("input", "Input STL file.").Short('i').Required().ExistingFile()
+	fitOutput  = fitCommand.Flag("output", "Output STL file pattern.").Short('o').Default("part%03d.stl").String()
+	fitVolume  = fitCommand.Flag("volume", "Build volume as XxYxZ.").Short('v').Required().String()
+	fitPlace   = fitCommand.Flag("place", "Save each part rotated and moved into the build volume.").Bool()
+)
+
+func fit() {
+	volume, err := parseVector(*fitVolume)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	mesh, err := fauxgl.LoadMesh(*fitInput)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	parts, err := choppy.FitVolume(mesh, volume)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%d parts\n", len(parts))
+	for i, part := range parts {
+		path := fmt.Sprintf(*fitOutput, i)
+		box := part.Mesh.BoundingBox()
+		// the size once rotated into the build volume, which is what fits
+		size := part.Box.Size()
+		fmt.Printf(
+			"%s: %d triangles, min (%g, %g, %g), max (%g, %g, %g), size in build volume %g x %g x %g\n",
+			path, len(part.Mesh.Triangles),
+			box.Min.X, box.Min.Y, box.Min.Z, box.Max.X, box.Max.Y, box.Max.Z,
+			size.X, size.Y, size.Z)
+		m := part.Mesh
+		if *fitPlace {
+			m = m.Copy()
+			m.Transform(part.Transform)
+		}
+		if err := m.SaveSTL(path); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"log"
+
+	"github.com/fogleman/choppy"
+	"github.com/fogleman/fauxgl"
+	kingpin "gopkg.in/alecthomas/kingpin.v2"
+)
+
+var (
+	jointCommand   = kingpin.Command("joint", "Chop a mesh in two with a lap, tongue-and-groove or dovetail joint in place of the flat caps.")
+	jointInput     = jointCommand.Flag("input", "Input STL file.").Short('i').Required().ExistingFile()
+	jointOutput    = jointCommand.Flag("output", "Output STL or OBJ file, numbered 1 for the front part and 2 for the back.").Short('o').Required().String()
+	jointNormal    = jointCommand.Flag("normal", "Plane normal, as X,Y,Z.").Short('n').Default("0,0,1").String()
+	jointOffset    = jointCommand.Flag("offset", "Plane offset from the lowest point along the normal.").Short('d').Required().Float64()
+	jointShape     = jointCommand.Flag("shape", "Joint shape.").Short('s').Default("tongue").Enum("lap", "tongue", "dovetail")
+	jointDepth     = jointCommand.Flag("depth", "How far the joint reaches past the plane.").Required().Float64()
+	jointClearance = jointCommand.Flag("clearance", "Gap between the parts where they slide together.").Default("0.2").Float64()
+	jointWall      = jointCommand.Flag("wall", "Wall thickness around the tongue.").Default("2").Float64()
+	jointAxis      = jointCommand.Flag("axis", "Direction the lap step and dovetail run along, as X,Y,Z.").Default("1,0,0").String()
+	jointWidth     = jointCommand.Flag("width", "Width of the dovetail at the plane.").Default("10").Float64()
+	jointAngle     = jointCommand.Flag("angle", "Angle the dovetail sides lean out by, in degrees.").Default("15").Float64()
+	jointCapColor  = jointCommand.Flag("cap-color", "Vertex color of the joint faces, as hex.").String()
+)
+
+func joint() {
+	normal, err := parseVector(*jointNormal)
+	if err != nil {
+		log.Fatal(err)
+	}
+	axis, err := parseVector(*jointAxis)
+	if err != nil {
+		log.Fatal(err)
+	}
+	shapes := map[string]choppy.JointShape{
+		"lap":      choppy.LapJoint,
+		"tongue":   choppy.TongueJoint,
+		"dovetail": choppy.DovetailJoint,
+	}
+	options := choppy.Options{Joint: &choppy.JointOptions{
+		Shape:     shapes[*jointShape],
+		Depth:     *jointDepth,
+		Clearance: *jointClearance,
+		Wall:      *jointWall,
+		Axis:      axis,
+		Width:     *jointWidth,
+		Angle:     *jointAngle,
+	}}
+	if *jointCapColor != "" {
+		options.CapColor = fauxgl.HexColor(*jointCapColor)
+	}
+
+	mesh, err := fauxgl.LoadMesh(*jointInput)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	plane := choppy.PlaneAtOffset(mesh, normal, *jointOffset)
+	front, back, err := plane.ChopWith(mesh, options)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for i, part := range []*fauxgl.Mesh{front, back} {
+		path := numberedPath(*jointOutput, i+1)
+		log.Printf("%s: %d triangles, volume %g", path, len(part.Triangles), part.Volume())
+		if err := saveMesh(path, part); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
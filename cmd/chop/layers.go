@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+
+	"github.com/fogleman/choppy"
+	"github.com/fogleman/fauxgl"
+	kingpin "gopkg.in/alecthomas/kingpin.v2"
+)
+
+var (
+	layersCommand      = kingpin.Command("layers", "Slice a mesh into sheets and export each layer as SVG or DXF.")
+	layersInput        = layersCommand.Flag("input", "Input STL file.").Short('i').Required().ExistingFile()
+	layersOutput       = layersCommand.Flag("output", "Output SVG or DXF file pattern.").Short('o').Default("layer%03d.svg").String()
+	layersNormal       = layersCommand.Flag("normal", "Stacking direction, as X,Y,Z.").Short('n').Default("0,0,1").String()
+	layersThickness    = layersCommand.Flag("thickness", "Sheet thickness.").Short('t').Required().Float64()
+	layersHoles        = layersCommand.Flag("holes", "Registration holes per polygon.").Default("2").Int()
+	layersHoleDiameter = layersCommand.Flag("hole-diameter", "Registration hole diameter.").Default("3").Float64()
+	layersMargin       = layersCommand.Flag("margin", "Distance kept between holes, contours and labels.").Default("1").Float64()
+	layersLabelSize    = layersCommand.Flag("label-size", "Layer number height, or 0 for none.").Default("4").Float64()
+)
+
+func layers() {
+	normal, err := parseVector(*layersNormal)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ext := strings.ToLower(filepath.Ext(*layersOutput))
+	if ext != ".svg" && ext != ".dxf" {
+		log.Fatal("output must be .svg or .dxf")
+	}
+
+	mesh, err := fauxgl.LoadMesh(*layersInput)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	options := choppy.LayerOptions{
+		Thickness:    *layersThickness,
+		Holes:        *layersHoles,
+		HoleDiameter: *layersHoleDiameter,
+		Margin:       *layersMargin,
+		LabelSize:    *layersLabelSize,
+	}
+	layers, err := choppy.Layers(mesh, normal, options)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("%d layers\n", len(layers))
+	for _, layer := range layers {
+		path := fmt.Sprintf(*layersOutput, layer.Number)
+		fmt.Printf(
+			"%s: offset %g, %d polygons, %d holes\n",
+			path, layer.Offset, len(layer.Polygons), len(layer.Holes))
+		if ext == ".svg" {
+			err = layer.SaveSVG(path)
+		} else {
+			err = layer.SaveDXF(path)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
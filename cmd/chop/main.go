@@ -9,23 +9,91 @@ import (
 )
 
 var (
-	z      = kingpin.Flag("z", "Z offset for slicing.").Short('z').Required().Float64()
-	input  = kingpin.Flag("input", "Input STL file.").Short('i').Required().ExistingFile()
-	output = kingpin.Flag("output", "Output STL file.").Short('o').Required().String()
+	cutCommand = kingpin.Command("cut", "Chop a mesh at a Z offset.").Default()
+	z          = cutCommand.Flag("z", "Z offset for slicing.").Short('z').Required().Float64()
+	input      = cutCommand.Flag("input", "Input STL file.").Short('i').Required().ExistingFile()
+	output     = cutCommand.Flag("output", "Output STL or OBJ file; OBJ keeps normals, texture coordinates and colors.").Short('o').Required().String()
+	strict     = cutCommand.Flag("strict", "Fail if the cross section is not closed.").Bool()
+	cutRepair  = cutCommand.Flag("repair", "Repair the mesh before chopping.").Bool()
+	capColor   = cutCommand.Flag("cap-color", "Vertex color of the cap, as hex.").String()
+	cutSplit   = cutCommand.Flag("split", "Save each connected piece to its own numbered file.").Bool()
 )
 
 func main() {
-	kingpin.Parse()
+	switch kingpin.Parse() {
+	case cutCommand.FullCommand():
+		cut()
+	case fitCommand.FullCommand():
+		fit()
+	case searchCommand.FullCommand():
+		search()
+	case profileCommand.FullCommand():
+		profile()
+	case sectionCommand.FullCommand():
+		section()
+	case layersCommand.FullCommand():
+		layers()
+	case rasterCommand.FullCommand():
+		raster()
+	case repairCommand.FullCommand():
+		repair()
+	case clipCommand.FullCommand():
+		clip()
+	case booleanCommand.FullCommand():
+		boolean()
+	case seamCommand.FullCommand():
+		seam()
+	case jointCommand.FullCommand():
+		joint()
+	}
+}
 
+func cut() {
 	mesh, err := fauxgl.LoadMesh(*input)
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	if *cutRepair {
+		var report *choppy.RepairReport
+		mesh, report = choppy.Repair(mesh, choppy.RepairOptions{})
+		log.Println(report)
+	}
+
 	z0 := mesh.BoundingBox().Min.Z
 	p := fauxgl.Vector{0, 0, z0 + *z}
 	n := fauxgl.Vector{0, 0, -1}
 
-	choppedMesh := choppy.Chop(mesh, p, n)
-	choppedMesh.SaveSTL(*output)
+	var options choppy.Options
+	if *capColor != "" {
+		options.CapColor = fauxgl.HexColor(*capColor)
+	}
+
+	choppedMesh, _, report, err := choppy.MakePlane(p, n).ChopWithReport(mesh, options)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if !report.Closed() {
+		log.Println(report)
+		for _, path := range report.OpenChains {
+			log.Printf("open chain: %v -> %v", path[0], path[len(path)-1])
+		}
+		if *strict {
+			log.Fatal("cross section is not closed")
+		}
+	}
+	if !*cutSplit {
+		if err := saveMesh(*output, choppedMesh); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	for i, piece := range choppy.Components(choppedMesh) {
+		path := numberedPath(*output, i+1)
+		box := piece.BoundingBox()
+		log.Printf("%s: volume %g, bounding box %v to %v", path, piece.Volume(), box.Min, box.Max)
+		if err := saveMesh(path, piece); err != nil {
+			log.Fatal(err)
+		}
+	}
 }
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/fogleman/choppy"
+	"github.com/fogleman/fauxgl"
+	kingpin "gopkg.in/alecthomas/kingpin.v2"
+)
+
+var (
+	profileCommand = kingpin.Command("profile", "Print cross section area and perimeter along a direction as CSV.")
+	profileInput   = profileCommand.Flag("input", "Input STL file.").Short('i').Required().ExistingFile()
+	profileNormal  = profileCommand.Flag("normal", "Slice direction, as X,Y,Z.").Short('n').Default("0,0,1").String()
+	profileCount   = profileCommand.Flag("count", "Number of slices.").Short('c').Default("100").Int()
+)
+
+func profile() {
+	normal, err := parseVector(*profileNormal)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	mesh, err := fauxgl.LoadMesh(*profileInput)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println("offset,area,perimeter,loops,holes")
+	for _, s := range choppy.Profile(mesh, normal, *profileCount) {
+		fmt.Printf("%g,%g,%g,%d,%d\n", s.Offset, s.Area, s.Perimeter, s.Loops, s.Holes)
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fogleman/choppy"
+	"github.com/fogleman/fauxgl"
+	kingpin "gopkg.in/alecthomas/kingpin.v2"
+)
+
+var (
+	rasterCommand     = kingpin.Command("raster", "Slice a mesh into PNG layers for resin printers.")
+	rasterInput       = rasterCommand.Flag("input", "Input STL file.").Short('i').Required().ExistingFile()
+	rasterOutput      = rasterCommand.Flag("output", "Output PNG file pattern or zip file.").Short('o').Default("layer%05d.png").String()
+	rasterLayerHeight = rasterCommand.Flag("layer-height", "Layer height.").Short('l').Default("0.05").Float64()
+	rasterPixelSize   = rasterCommand.Flag("pixel-size", "Pixel size.").Short('p').Default("0.05").Float64()
+	rasterResolution  = rasterCommand.Flag("resolution", "Image resolution as WxH.").Short('r').Default("2560x1440").String()
+	rasterAntialias   = rasterCommand.Flag("antialias", "Anti-alias layer edges.").Bool()
+)
+
+func raster() {
+	var width, height int
+	if _, err := fmt.Sscanf(*rasterResolution, "%dx%d", &width, &height); err != nil {
+		log.Fatalf("invalid resolution: %s", *rasterResolution)
+	}
+
+	mesh, err := fauxgl.LoadMesh(*rasterInput)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	options := choppy.RasterOptions{
+		LayerHeight: *rasterLayerHeight,
+		PixelSize:   *rasterPixelSize,
+		Width:       width,
+		Height:      height,
+		Antialias:   *rasterAntialias,
+	}
+	layers, err := choppy.RasterLayers(mesh, options)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// per layer area report
+	var volume float64
+	fmt.Println("layer,z,area")
+	for _, layer := range layers {
+		fmt.Printf("%d,%g,%g\n", layer.Number, layer.Z, layer.Area)
+		volume += layer.Area * options.LayerHeight
+	}
+	log.Printf("%d layers, resin volume %g", len(layers), volume)
+
+	if strings.ToLower(filepath.Ext(*rasterOutput)) == ".zip" {
+		file, err := os.Create(*rasterOutput)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer file.Close()
+		if err := choppy.WriteRasterZip(file, layers); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	for _, layer := range layers {
+		if err := layer.SavePNG(fmt.Sprintf(*rasterOutput, layer.Number)); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"log"
+
+	"github.com/fogleman/choppy"
+	"github.com/fogleman/fauxgl"
+	kingpin "gopkg.in/alecthomas/kingpin.v2"
+)
+
+var (
+	repairCommand      = kingpin.Command("repair", "Weld, clean, orient and close a mesh.")
+	repairInput        = repairCommand.Flag("input", "Input STL file.").Short('i').Required().ExistingFile()
+	repairOutput       = repairCommand.Flag("output", "Output STL file.").Short('o').Required().String()
+	repairWeldDistance = repairCommand.Flag("weld", "Weld distance, or 0 for automatic.").Default("0").Float64()
+	repairMaxHoleEdges = repairCommand.Flag("max-hole-edges", "Largest boundary loop to fill.").Default("16").Int()
+)
+
+func repair() {
+	mesh, err := fauxgl.LoadMesh(*repairInput)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	options := choppy.RepairOptions{
+		WeldDistance: *repairWeldDistance,
+		MaxHoleEdges: *repairMaxHoleEdges,
+	}
+	mesh, report := choppy.Repair(mesh, options)
+	log.Println(report)
+	if err := mesh.SaveSTL(*repairOutput); err != nil {
+		log.Fatal(err)
+	}
+}
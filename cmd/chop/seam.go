@@ -0,0 +1,67 @@
+package main
+
+import (
+	"log"
+
+	"github.com/fogleman/choppy"
+	"github.com/fogleman/fauxgl"
+	kingpin "gopkg.in/alecthomas/kingpin.v2"
+)
+
+var (
+	seamCommand   = kingpin.Command("seam", "Split a mesh along a zigzag, sine, jigsaw or custom profile swept along a direction.")
+	seamInput     = seamCommand.Flag("input", "Input STL file.").Short('i').Required().ExistingFile()
+	seamOutput    = seamCommand.Flag("output", "Output STL or OBJ file, numbered for each part.").Short('o').Required().String()
+	seamPoint     = seamCommand.Flag("point", "Point the seam passes through, as X,Y,Z; defaults to the center of the mesh.").Short('p').String()
+	seamNormal    = seamCommand.Flag("normal", "Direction the profile is swept along, as X,Y,Z.").Short('n').Default("0,0,1").String()
+	seamAlong     = seamCommand.Flag("along", "Direction the seam runs across the mesh, as X,Y,Z.").Default("1,0,0").String()
+	seamShape     = seamCommand.Flag("shape", "Profile shape.").Short('s').Default("zigzag").Enum("zigzag", "sine", "jigsaw")
+	seamAmplitude = seamCommand.Flag("amplitude", "Profile amplitude.").Short('a').Default("5").Float64()
+	seamPeriod    = seamCommand.Flag("period", "Profile period.").Short('t').Default("20").Float64()
+	seamProfile   = seamCommand.Flag("profile", "JSON profile file, overriding the shape flags.").ExistingFile()
+)
+
+func seam() {
+	normal, err := parseVector(*seamNormal)
+	if err != nil {
+		log.Fatal(err)
+	}
+	along, err := parseVector(*seamAlong)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	profile := choppy.SeamProfile{Shape: *seamShape, Amplitude: *seamAmplitude, Period: *seamPeriod}
+	if *seamProfile != "" {
+		profile, err = choppy.LoadSeamProfile(*seamProfile)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	mesh, err := fauxgl.LoadMesh(*seamInput)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	point := mesh.BoundingBox().Center()
+	if *seamPoint != "" {
+		point, err = parseVector(*seamPoint)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	plane := choppy.MakePlaneAlong(point, normal, along)
+	above, below, err := plane.ChopSeam(mesh, profile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for i, part := range []*fauxgl.Mesh{above, below} {
+		path := numberedPath(*seamOutput, i+1)
+		log.Printf("%s: %d triangles, volume %g", path, len(part.Triangles), part.Volume())
+		if err := saveMesh(path, part); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
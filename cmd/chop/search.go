@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/fogleman/choppy"
+	"github.com/fogleman/fauxgl"
+	kingpin "gopkg.in/alecthomas/kingpin.v2"
+)
+
+var (
+	searchCommand = kingpin.Command("search", "Find the best cut plane and print the cost curve as CSV.")
+	searchInput   = searchCommand.Flag("input", "Input STL file.").Short('i').Required().ExistingFile()
+	searchCost    = searchCommand.Flag("cost", "Cost to minimize.").Short('c').Default("area").Enum("area", "loops", "thickness", "balance")
+	searchNormal  = searchCommand.Flag("normal", "Only try this plane normal, as X,Y,Z.").Short('n').String()
+	searchSamples = searchCommand.Flag("samples", "Number of directions to try.").Default("64").Int()
+	searchSteps   = searchCommand.Flag("steps", "Number of offsets to try per direction.").Default("32").Int()
+)
+
+var costs = map[string]choppy.Cost{
+	"area":      choppy.AreaCost,
+	"loops":     choppy.LoopsCost,
+	"thickness": choppy.ThicknessCost,
+	"balance":   choppy.BalanceCost,
+}
+
+func search() {
+	mesh, err := fauxgl.LoadMesh(*searchInput)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	options := choppy.SearchOptions{
+		Cost:    costs[*searchCost],
+		Samples: *searchSamples,
+		Steps:   *searchSteps,
+	}
+	if *searchNormal != "" {
+		normal, err := parseVector(*searchNormal)
+		if err != nil {
+			log.Fatal(err)
+		}
+		options.Directions = []fauxgl.Vector{normal}
+	}
+
+	result := choppy.Search(mesh, options)
+	fmt.Println("nx,ny,nz,offset,cost")
+	for _, c := range result.Candidates {
+		n := c.Plane.Normal
+		fmt.Printf("%g,%g,%g,%g,%g\n", n.X, n.Y, n.Z, c.Offset, c.Cost)
+	}
+
+	best := result.Best
+	p := best.Plane.Point
+	n := best.Plane.Normal
+	log.Printf(
+		"best: point (%g, %g, %g), normal (%g, %g, %g), offset %g, cost %g",
+		p.X, p.Y, p.Z, n.X, n.Y, n.Z, best.Offset, best.Cost)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"log"
+	"path/filepath"
+	"strings"
+
+	"github.com/fogleman/choppy"
+	"github.com/fogleman/fauxgl"
+	kingpin "gopkg.in/alecthomas/kingpin.v2"
+)
+
+var (
+	sectionCommand = kingpin.Command("section", "Export a cross section as SVG or DXF.")
+	sectionInput   = sectionCommand.Flag("input", "Input STL file.").Short('i').Required().ExistingFile()
+	sectionOutput  = sectionCommand.Flag("output", "Output SVG or DXF file.").Short('o').Required().String()
+	sectionNormal  = sectionCommand.Flag("normal", "Plane normal, as X,Y,Z.").Short('n').Default("0,0,1").String()
+	sectionOffset  = sectionCommand.Flag("offset", "Plane offset from the lowest point along the normal.").Short('d').Required().Float64()
+)
+
+func section() {
+	normal, err := parseVector(*sectionNormal)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	mesh, err := fauxgl.LoadMesh(*sectionInput)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	plane := choppy.PlaneAtOffset(mesh, normal, *sectionOffset)
+	polygons := choppy.Section(mesh, plane)
+	log.Printf("%d polygons", len(polygons))
+
+	switch strings.ToLower(filepath.Ext(*sectionOutput)) {
+	case ".svg":
+		err = choppy.SaveSVG(*sectionOutput, polygons)
+	case ".dxf":
+		err = choppy.SaveDXF(*sectionOutput, polygons)
+	default:
+		log.Fatal("output must be .svg or .dxf")
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/fogleman/choppy"
+	"github.com/fogleman/fauxgl"
+)
+
+// parses "X,Y,Z" or "XxYxZ"
+func parseVector(value string) (fauxgl.Vector, error) {
+	fields := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == 'x' || r == 'X'
+	})
+	if len(fields) != 3 {
+		return fauxgl.Vector{}, fmt.Errorf("invalid vector: %s", value)
+	}
+	var v [3]float64
+	for i, field := range fields {
+		x, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+		if err != nil {
+			return fauxgl.Vector{}, fmt.Errorf("invalid vector: %s", value)
+		}
+		v[i] = x
+	}
+	return fauxgl.Vector{v[0], v[1], v[2]}, nil
+}
+
+// saves OBJ files with their vertex attributes, and STL otherwise
+func saveMesh(path string, mesh *fauxgl.Mesh) error {
+	if strings.ToLower(filepath.Ext(path)) == ".obj" {
+		return choppy.SaveOBJ(path, mesh)
+	}
+	return mesh.SaveSTL(path)
+}
+
+// "out.stl" becomes "out-1.stl"
+func numberedPath(path string, i int) string {
+	ext := filepath.Ext(path)
+	return fmt.Sprintf("%s-%d%s", strings.TrimSuffix(path, ext), i, ext)
+}
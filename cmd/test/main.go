@@ -18,8 +18,7 @@ func main() {
 	point := fauxgl.Vector{0, 0, 0}
 	normal := fauxgl.Vector{1, 1, 1}.Normalize()
 
-	m1 := choppy.Chop(mesh, point, normal)
-	m2 := choppy.Chop(mesh, point, normal.Negate())
+	m1, m2 := choppy.ChopBoth(mesh, point, normal)
 
 	m1.SaveSTL("out1.stl")
 	m2.SaveSTL("out2.stl")
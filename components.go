@@ -0,0 +1,120 @@
+package choppy
+
+import (
+	"sort"
+
+	"github.com/fogleman/fauxgl"
+)
+
+// splits the mesh into the bodies it is made of; triangles sharing a vertex
+// are one shell, so bodies touching at a single vertex stay one body, and
+// shells facing inward are voids that stay with the smallest body around them
+func Components(mesh *fauxgl.Mesh) []*fauxgl.Mesh {
+	vertices, faces, _ := weldVertices(mesh, defaultTolerance(mesh))
+	parents := make([]int, len(vertices))
+	for i := range parents {
+		parents[i] = i
+	}
+	find := func(i int) int {
+		for parents[i] != i {
+			parents[i] = parents[parents[i]]
+			i = parents[i]
+		}
+		return i
+	}
+	union := func(a, b int) {
+		a, b = find(a), find(b)
+		if a != b {
+			parents[b] = a
+		}
+	}
+	for _, f := range faces {
+		union(f[0], f[1])
+		union(f[0], f[2])
+	}
+
+	// shells in the order of their first triangle
+	lookup := make(map[int]int)
+	var shells [][]int
+	for i, f := range faces {
+		root := find(f[0])
+		j, ok := lookup[root]
+		if !ok {
+			j = len(shells)
+			lookup[root] = j
+			shells = append(shells, nil)
+		}
+		shells[j] = append(shells[j], i)
+	}
+	if len(shells) <= 1 {
+		return []*fauxgl.Mesh{mesh}
+	}
+
+	volumes := make([]float64, len(shells))
+	boxes := make([]fauxgl.Box, len(shells))
+	for i, shell := range shells {
+		box := fauxgl.EmptyBox
+		for _, j := range shell {
+			t := mesh.Triangles[j]
+			p1, p2, p3 := t.V1.Position, t.V2.Position, t.V3.Position
+			volumes[i] += p1.Dot(p2.Cross(p3)) / 6
+			box = box.Extend(t.BoundingBox())
+		}
+		boxes[i] = box
+	}
+
+	// voids join the smallest body containing them, or stand alone if none do
+	owners := make([]int, len(shells))
+	for i, shell := range shells {
+		owners[i] = i
+		if volumes[i] >= 0 {
+			continue
+		}
+		p := mesh.Triangles[shell[0]].V1.Position
+		for j, other := range shells {
+			if volumes[j] <= 0 || !boxes[j].Contains(p) {
+				continue
+			}
+			if owners[i] != i && volumes[j] >= volumes[owners[i]] {
+				continue
+			}
+			if shellContains(mesh, other, p) {
+				owners[i] = j
+			}
+		}
+	}
+
+	var bodies [][]int
+	index := make(map[int]int)
+	for i, shell := range shells {
+		j, ok := index[owners[i]]
+		if !ok {
+			j = len(bodies)
+			index[owners[i]] = j
+			bodies = append(bodies, nil)
+		}
+		bodies[j] = append(bodies[j], shell...)
+	}
+	result := make([]*fauxgl.Mesh, len(bodies))
+	for i, body := range bodies {
+		sort.Ints(body)
+		triangles := make([]*fauxgl.Triangle, len(body))
+		for j, k := range body {
+			triangles[j] = mesh.Triangles[k]
+		}
+		result[i] = fauxgl.NewTriangleMesh(triangles)
+	}
+	return result
+}
+
+// ray parity test of p against the triangles of a shell
+func shellContains(mesh *fauxgl.Mesh, shell []int, p fauxgl.Vector) bool {
+	inside := false
+	for _, i := range shell {
+		t := mesh.Triangles[i]
+		if rayHitsTriangle(p, rayDirection, t.V1.Position, t.V2.Position, t.V3.Position) {
+			inside = !inside
+		}
+	}
+	return inside
+}
@@ -0,0 +1,47 @@
+package choppy
+
+import (
+	"testing"
+
+	"github.com/fogleman/fauxgl"
+)
+
+func TestComponents(t *testing.T) {
+	join := func(meshes ...*fauxgl.Mesh) *fauxgl.Mesh {
+		var triangles []*fauxgl.Triangle
+		for _, m := range meshes {
+			triangles = append(triangles, m.Triangles...)
+		}
+		return fauxgl.NewTriangleMesh(triangles)
+	}
+
+	// the arms of a U chopped off above its base, each with its own cap
+	plane := MakePlaneAlong(fauxgl.Vector{}, fauxgl.Vector{0, 0, -1}, fauxgl.Vector{1, 0, 0})
+	u := plane.prism(Path{
+		{0, 0, 0}, {30, 0, 0}, {30, 20, 0}, {20, 20, 0},
+		{20, 10, 0}, {10, 10, 0}, {10, 20, 0}, {0, 20, 0}}, -10, 0)
+	arms := MakePlane(fauxgl.Vector{0, 15, 0}, fauxgl.Vector{0, 1, 0}).Chop(u)
+
+	cases := []struct {
+		name    string
+		mesh    *fauxgl.Mesh
+		volumes []float64
+	}{
+		{"disjoint", join(testBox(fauxgl.Vector{}, fauxgl.Vector{10, 10, 10}), testBox(fauxgl.Vector{20, 0, 0}, fauxgl.Vector{30, 10, 10})), []float64{1000, 1000}},
+		{"touching at a vertex", join(testBox(fauxgl.Vector{}, fauxgl.Vector{10, 10, 10}), testBox(fauxgl.Vector{10, 10, 10}, fauxgl.Vector{20, 20, 20})), []float64{2000}},
+		{"void", join(testBox(fauxgl.Vector{}, fauxgl.Vector{20, 20, 20}), testVoid(fauxgl.Vector{5, 5, 5}, fauxgl.Vector{15, 15, 15}), testBox(fauxgl.Vector{30, 0, 0}, fauxgl.Vector{40, 10, 10})), []float64{7000, 1000}},
+		// a body inside the void of another is a body of its own
+		{"body in a void", join(testBox(fauxgl.Vector{}, fauxgl.Vector{20, 20, 20}), testVoid(fauxgl.Vector{2, 2, 2}, fauxgl.Vector{18, 18, 18}), testBox(fauxgl.Vector{5, 5, 5}, fauxgl.Vector{15, 15, 15})), []float64{8000 - 16*16*16, 1000}},
+		{"chopped U", arms, []float64{500, 500}},
+	}
+	for _, c := range cases {
+		bodies := Components(c.mesh)
+		if len(bodies) != len(c.volumes) {
+			t.Errorf("%s: %d bodies, want %d", c.name, len(bodies), len(c.volumes))
+			continue
+		}
+		for i, body := range bodies {
+			checkClosed(t, c.name, body, c.volumes[i])
+		}
+	}
+}
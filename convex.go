@@ -0,0 +1,51 @@
+package choppy
+
+import (
+	"errors"
+
+	"github.com/fogleman/fauxgl"
+)
+
+// keeps the part of the mesh in front of every plane, capping each plane
+// that cuts it; chopping by one plane at a time clips the earlier caps by
+// the later planes so the caps meet along the edges of the polytope
+func ClipConvex(mesh *fauxgl.Mesh, planes []Plane) *fauxgl.Mesh {
+	result, _ := ClipConvexWith(mesh, planes, Options{})
+	return result
+}
+
+// ClipConvex with the tolerance and cap color of the options; the other
+// options do not apply and are an error
+func ClipConvexWith(mesh *fauxgl.Mesh, planes []Plane, options Options) (*fauxgl.Mesh, error) {
+	if options.Pegs != nil || options.Joint != nil || options.Kerf != 0 || options.KerfOffset != 0 {
+		return nil, errors.New("pegs, joints and kerfs do not apply to clipping")
+	}
+	// snap with the tolerance of the whole mesh, not of what is left
+	if options.Tolerance <= 0 {
+		options.Tolerance = defaultTolerance(mesh)
+	}
+	result := mesh
+	for _, plane := range planes {
+		if len(result.Triangles) == 0 {
+			break
+		}
+		result = plane.cut(mesh, options).chop(result, nil)
+	}
+	return result, nil
+}
+
+func ClipBox(mesh *fauxgl.Mesh, box fauxgl.Box) *fauxgl.Mesh {
+	return ClipConvex(mesh, BoxPlanes(box))
+}
+
+// planes of the faces of the box, facing inward
+func BoxPlanes(box fauxgl.Box) []Plane {
+	return []Plane{
+		MakePlane(box.Min, fauxgl.Vector{1, 0, 0}),
+		MakePlane(box.Max, fauxgl.Vector{-1, 0, 0}),
+		MakePlane(box.Min, fauxgl.Vector{0, 1, 0}),
+		MakePlane(box.Max, fauxgl.Vector{0, -1, 0}),
+		MakePlane(box.Min, fauxgl.Vector{0, 0, 1}),
+		MakePlane(box.Max, fauxgl.Vector{0, 0, -1}),
+	}
+}
@@ -0,0 +1,72 @@
+package choppy
+
+import (
+	"math"
+	"testing"
+
+	"github.com/fogleman/fauxgl"
+)
+
+func TestClipConvex(t *testing.T) {
+	cube := testBox(fauxgl.Vector{}, fauxgl.Vector{20, 20, 20})
+	sphere := testSphere(fauxgl.Vector{}, 10, 16)
+	cases := []struct {
+		name   string
+		mesh   *fauxgl.Mesh
+		planes []Plane
+		volume float64
+	}{
+		{"box inside", cube, BoxPlanes(fauxgl.Box{fauxgl.Vector{2.5, 3.5, 4.5}, fauxgl.Vector{12.5, 13.5, 14.5}}), 1000},
+		{"box over a corner", cube, BoxPlanes(fauxgl.Box{fauxgl.Vector{15, 15, 15}, fauxgl.Vector{30, 30, 30}}), 125},
+		// on the faces of the cube
+		{"flush box", cube, BoxPlanes(fauxgl.Box{fauxgl.Vector{0, 0, 0}, fauxgl.Vector{10, 20, 20}}), 4000},
+		{"octant of the sphere", sphere, BoxPlanes(fauxgl.Box{fauxgl.Vector{}, fauxgl.Vector{20, 20, 20}}), sphere.Volume() / 8},
+		{"corner wedge", cube, []Plane{
+			MakePlane(fauxgl.Vector{10, 10, 10}, fauxgl.Vector{-1, -1, 0}),
+			MakePlane(fauxgl.Vector{10, 10, 10}, fauxgl.Vector{0, 0, -1}),
+		}, 2000},
+		{"outside", cube, BoxPlanes(fauxgl.Box{fauxgl.Vector{30, 30, 30}, fauxgl.Vector{40, 40, 40}}), 0},
+	}
+	for _, c := range cases {
+		m := ClipConvex(c.mesh, c.planes)
+		if c.volume == 0 {
+			if len(m.Triangles) != 0 {
+				t.Errorf("%s: %d triangles", c.name, len(m.Triangles))
+			}
+			continue
+		}
+		checkClosed(t, c.name, m, c.volume)
+	}
+
+	// caps of the box meet along its edges
+	m := ClipBox(sphere, fauxgl.Box{fauxgl.Vector{-5, -5, -5}, fauxgl.Vector{5, 5, 5}})
+	if v := m.Volume(); math.Abs(v-1000) > 1e-9 {
+		t.Errorf("box in the sphere: volume %g", v)
+	}
+}
+
+func TestClipConvexWithOptions(t *testing.T) {
+	cube := testBox(fauxgl.Vector{}, fauxgl.Vector{20, 20, 20})
+	planes := BoxPlanes(fauxgl.Box{fauxgl.Vector{5, 5, 5}, fauxgl.Vector{15, 15, 15}})
+	for _, options := range []Options{
+		{Pegs: &PegOptions{}},
+		{Joint: &JointOptions{Shape: LapJoint, Depth: 3}},
+		{Kerf: 1},
+		{KerfOffset: 1},
+	} {
+		if _, err := ClipConvexWith(cube, planes, options); err == nil {
+			t.Errorf("%+v: no error", options)
+		}
+	}
+	red := fauxgl.HexColor("#ff0000")
+	m, err := ClipConvexWith(cube, planes, Options{CapColor: red})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkClosed(t, "colored", m, 1000)
+	for _, u := range m.Triangles {
+		if u.V1.Color != red {
+			t.Fatalf("cap color %v, want %v", u.V1.Color, red)
+		}
+	}
+}
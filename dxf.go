@@ -0,0 +1,72 @@
+package choppy
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+)
+
+func SaveDXF(path string, polygons []Polygon) error {
+	return saveDXF(path, polygons, nil)
+}
+
+func WriteDXF(w io.Writer, polygons []Polygon) error {
+	return writeDXF(w, polygons, nil)
+}
+
+func saveDXF(path string, polygons []Polygon, labels []label) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return writeDXF(file, polygons, labels)
+}
+
+// writes polygons as closed R12 polylines, each exterior and interior as its
+// own polyline; coordinates are in millimeters, but R12 has no header
+// variable to say so, and importers reject $INSUNITS in an R12 file
+func writeDXF(w io.Writer, polygons []Polygon, labels []label) error {
+	bw := bufio.NewWriter(w)
+	pair := func(code int, value interface{}) {
+		fmt.Fprintf(bw, "%d\n%v\n", code, value)
+	}
+	pair(0, "SECTION")
+	pair(2, "HEADER")
+	pair(9, "$ACADVER")
+	pair(1, "AC1009")
+	pair(0, "ENDSEC")
+	pair(0, "SECTION")
+	pair(2, "ENTITIES")
+	for _, polygon := range polygons {
+		for _, path := range polygon.paths() {
+			pair(0, "POLYLINE")
+			pair(8, "0")
+			pair(66, 1)
+			pair(70, 1)
+			for _, p := range path {
+				pair(0, "VERTEX")
+				pair(8, "0")
+				pair(10, p.X)
+				pair(20, p.Y)
+			}
+			pair(0, "SEQEND")
+		}
+	}
+	for _, l := range labels {
+		pair(0, "TEXT")
+		pair(8, "LABELS")
+		pair(10, l.Position.X)
+		pair(20, l.Position.Y)
+		pair(40, l.Size)
+		pair(1, l.Text)
+		pair(72, 1)
+		pair(11, l.Position.X)
+		pair(21, l.Position.Y)
+		pair(73, 2)
+	}
+	pair(0, "ENDSEC")
+	pair(0, "EOF")
+	return bw.Flush()
+}
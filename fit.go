@@ -0,0 +1,222 @@
+package choppy
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/fogleman/fauxgl"
+)
+
+type FitPart struct {
+	*Part
+
+	// rotates the part to line up with the build volume and moves the
+	// minimum of its box to the origin
+	Transform fauxgl.Matrix
+
+	// box of the part once transformed
+	Box fauxgl.Box
+}
+
+// splits mesh with a grid of planes so that every part fits inside a build
+// volume of the given size once transformed; the grid is aligned either with
+// the mesh axes or with its principal axes, whichever needs fewer parts, and
+// the transform turns the grid axes onto the volume axes they were sized for
+func FitVolume(mesh *fauxgl.Mesh, volume fauxgl.Vector) ([]*FitPart, error) {
+	if volume.X <= 0 || volume.Y <= 0 || volume.Z <= 0 {
+		return nil, errors.New("build volume must be positive")
+	}
+	planes, rotation := fitGrid(mesh, volume)
+	limit := volume.MulScalar(1 + 1e-9)
+	var result []*FitPart
+	for _, part := range ChopMany(mesh, planes) {
+		rotated := part.Mesh.Copy()
+		rotated.Transform(rotation)
+		box := rotated.BoundingBox()
+		size := box.Size()
+		if size.X > limit.X || size.Y > limit.Y || size.Z > limit.Z {
+			return nil, fmt.Errorf("part of size %g x %g x %g does not fit the build volume", size.X, size.Y, size.Z)
+		}
+		transform := rotation.Translate(box.Min.Negate())
+		result = append(result, &FitPart{part, transform, fauxgl.Box{fauxgl.Vector{}, size}})
+	}
+	return result, nil
+}
+
+func FitPlanes(mesh *fauxgl.Mesh, volume fauxgl.Vector) []Plane {
+	planes, _ := fitGrid(mesh, volume)
+	return planes
+}
+
+// grid planes of the frame needing the fewest parts, and the rotation taking
+// the frame's axes onto the volume axes they were sized for
+func fitGrid(mesh *fauxgl.Mesh, volume fauxgl.Vector) ([]Plane, fauxgl.Matrix) {
+	if len(mesh.Triangles) == 0 {
+		return nil, fauxgl.Identity()
+	}
+	frames := [][3]fauxgl.Vector{
+		{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}},
+		principalAxes(mesh),
+	}
+	var result []Plane
+	var rotation fauxgl.Matrix
+	best := 0
+	for _, axes := range frames {
+		planes, perm, count := gridPlanes(mesh, axes, volume)
+		if best == 0 || count < best {
+			result = planes
+			rotation = frameRotation(axes, perm)
+			best = count
+		}
+	}
+	return result, rotation
+}
+
+// rotation taking axes[i] onto volume axis perm[i], up to the sign of the
+// last one
+func frameRotation(axes [3]fauxgl.Vector, perm [3]int) fauxgl.Matrix {
+	unit := [3]fauxgl.Vector{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+	first := rotateTo(axes[0], unit[perm[0]], axes[0].Perpendicular())
+	// turning the second axis about the first, which is now in place
+	a := first.MulDirection(axes[1])
+	second := rotateTo(a, unit[perm[1]], unit[perm[0]])
+	return second.Mul(first)
+}
+
+// fauxgl.RotateTo for unit vectors, turning about axis when they are
+// opposite and guarding acos against dot products just past one
+func rotateTo(a, b, axis fauxgl.Vector) fauxgl.Matrix {
+	dot := a.Dot(b)
+	switch {
+	case dot > 1-1e-12:
+		return fauxgl.Identity()
+	case dot < -1+1e-12:
+		return fauxgl.Rotate(axis, math.Pi)
+	}
+	return fauxgl.RotateTo(a, b)
+}
+
+// evenly spaced planes along each axis, using the assignment of volume
+// dimensions to axes that needs the fewest grid cells
+func gridPlanes(mesh *fauxgl.Mesh, axes [3]fauxgl.Vector, volume fauxgl.Vector) ([]Plane, [3]int, int) {
+	var lo, hi [3]float64
+	for i, axis := range axes {
+		lo[i], hi[i] = extent(mesh, axis)
+	}
+
+	sizes := [3]float64{volume.X, volume.Y, volume.Z}
+	permutations := [][3]int{
+		{0, 1, 2}, {0, 2, 1}, {1, 0, 2}, {1, 2, 0}, {2, 0, 1}, {2, 1, 0},
+	}
+	var counts, assignment [3]int
+	best := 0
+	for _, perm := range permutations {
+		var c [3]int
+		n := 1
+		for i := range axes {
+			c[i] = int(math.Ceil((hi[i]-lo[i])/sizes[perm[i]] - 1e-9))
+			if c[i] < 1 {
+				c[i] = 1
+			}
+			n *= c[i]
+		}
+		if best == 0 || n < best {
+			counts = c
+			assignment = perm
+			best = n
+		}
+	}
+
+	var planes []Plane
+	for i, axis := range axes {
+		for j := 1; j < counts[i]; j++ {
+			d := lo[i] + (hi[i]-lo[i])*float64(j)/float64(counts[i])
+			planes = append(planes, MakePlane(axis.MulScalar(d), axis))
+		}
+	}
+	return planes, assignment, best
+}
+
+// eigenvectors of the covariance of the mesh surface
+func principalAxes(mesh *fauxgl.Mesh) [3]fauxgl.Vector {
+	var area float64
+	var mean fauxgl.Vector
+	var moment [3][3]float64
+	for _, t := range mesh.Triangles {
+		a := t.Area()
+		v := [3]fauxgl.Vector{t.V1.Position, t.V2.Position, t.V3.Position}
+		s := v[0].Add(v[1]).Add(v[2])
+		area += a
+		mean = mean.Add(s.MulScalar(a / 3))
+		// integral of x x^T over the triangle
+		for _, p := range []fauxgl.Vector{v[0], v[1], v[2], s} {
+			q := [3]float64{p.X, p.Y, p.Z}
+			for i := 0; i < 3; i++ {
+				for j := 0; j < 3; j++ {
+					moment[i][j] += a / 12 * q[i] * q[j]
+				}
+			}
+		}
+	}
+	var axes [3]fauxgl.Vector
+	if area == 0 {
+		return [3]fauxgl.Vector{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+	}
+	mean = mean.DivScalar(area)
+	m := [3]float64{mean.X, mean.Y, mean.Z}
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			moment[i][j] = moment[i][j]/area - m[i]*m[j]
+		}
+	}
+	e := jacobiEigenvectors(moment)
+	for i := range axes {
+		axes[i] = fauxgl.Vector{e[0][i], e[1][i], e[2][i]}.Normalize()
+	}
+	return axes
+}
+
+// columns of the result are the eigenvectors of the symmetric matrix a
+func jacobiEigenvectors(a [3][3]float64) [3][3]float64 {
+	v := [3][3]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+	for sweep := 0; sweep < 50; sweep++ {
+		off := a[0][1]*a[0][1] + a[0][2]*a[0][2] + a[1][2]*a[1][2]
+		if off < 1e-30 {
+			break
+		}
+		for p := 0; p < 2; p++ {
+			for q := p + 1; q < 3; q++ {
+				if a[p][q] == 0 {
+					continue
+				}
+				theta := (a[q][q] - a[p][p]) / (2 * a[p][q])
+				t := 1 / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				if theta < 0 {
+					t = -t
+				}
+				c := 1 / math.Sqrt(t*t+1)
+				s := t * c
+				for k := 0; k < 3; k++ {
+					akp := a[k][p]
+					akq := a[k][q]
+					a[k][p] = c*akp - s*akq
+					a[k][q] = s*akp + c*akq
+				}
+				for k := 0; k < 3; k++ {
+					apk := a[p][k]
+					aqk := a[q][k]
+					a[p][k] = c*apk - s*aqk
+					a[q][k] = s*apk + c*aqk
+				}
+				for k := 0; k < 3; k++ {
+					vkp := v[k][p]
+					vkq := v[k][q]
+					v[k][p] = c*vkp - s*vkq
+					v[k][q] = s*vkp + c*vkq
+				}
+			}
+		}
+	}
+	return v
+}
@@ -0,0 +1,56 @@
+package choppy
+
+import (
+	"math"
+	"testing"
+
+	"github.com/fogleman/fauxgl"
+)
+
+func TestFitVolume(t *testing.T) {
+	// a bar along a diagonal only fits in few parts along its own axes; the
+	// grid is evenly spaced, so each part of a box is an equal share of it
+	bar := testBox(fauxgl.Vector{0, 0, 0}, fauxgl.Vector{100, 10, 6})
+	bar.Transform(fauxgl.Rotate(fauxgl.Vector{1, 2, 3}, 0.7))
+	cases := []struct {
+		name   string
+		mesh   *fauxgl.Mesh
+		volume fauxgl.Vector
+		parts  int
+		cell   float64
+	}{
+		{"cube", testBox(fauxgl.Vector{}, fauxgl.Vector{20, 20, 20}), fauxgl.Vector{10, 15, 30}, 4, 10 * 10 * 20},
+		{"tilted bar", bar, fauxgl.Vector{12, 40, 8}, 3, 100 * 10 * 6 / 3},
+	}
+	for _, c := range cases {
+		parts, err := FitVolume(c.mesh, c.volume)
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+		if len(parts) != c.parts {
+			t.Errorf("%s: %d parts, want %d", c.name, len(parts), c.parts)
+		}
+		var total float64
+		for _, part := range parts {
+			checkClosed(t, c.name, part.Mesh, c.cell)
+			total += part.Mesh.Volume()
+			placed := part.Mesh.Copy()
+			placed.Transform(part.Transform)
+			box := placed.BoundingBox()
+			size := box.Size()
+			if box.Min.Length() > 1e-6 || size.X > c.volume.X+1e-6 || size.Y > c.volume.Y+1e-6 || size.Z > c.volume.Z+1e-6 {
+				t.Errorf("%s: placed part %v does not fit %v", c.name, box, c.volume)
+			}
+			if size.Sub(part.Box.Size()).Length() > 1e-6 {
+				t.Errorf("%s: box %v, placed %v", c.name, part.Box, box)
+			}
+		}
+		if want := c.mesh.Volume(); math.Abs(total-want) > 1e-6*want {
+			t.Errorf("%s: total volume %g, want %g", c.name, total, want)
+		}
+	}
+	if _, err := FitVolume(bar, fauxgl.Vector{10, 0, 10}); err == nil {
+		t.Error("empty build volume: no error")
+	}
+}
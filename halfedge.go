@@ -0,0 +1,310 @@
+package choppy
+
+import (
+	"github.com/fogleman/fauxgl"
+)
+
+// indexed triangle mesh with half-edge connectivity; face f owns half-edges
+// 3f, 3f+1 and 3f+2
+type HalfEdgeMesh struct {
+	Vertices  []fauxgl.Vector
+	Faces     [][3]int
+	HalfEdges []HalfEdge
+
+	// half-edges leaving each vertex
+	outgoing [][]int
+
+	// triangle each face was cut from, which Mesh interpolates the vertex
+	// attributes of; nil, or -1 for faces added without one
+	triangles []*fauxgl.Triangle
+	sources   []int
+
+	nonManifoldEdges int
+}
+
+type HalfEdge struct {
+	Origin int
+	Face   int
+	Next   int
+
+	// -1 on the boundary, and on edges shared by more than two faces or by
+	// two faces with inconsistent winding
+	Twin int
+}
+
+// welds vertices closer than distance; 0 uses 1e-9 of the bounding box
+// diagonal
+func NewHalfEdgeMesh(mesh *fauxgl.Mesh, distance float64) *HalfEdgeMesh {
+	if distance <= 0 {
+		distance = defaultTolerance(mesh)
+	}
+	vertices, faces, _ := weldVertices(mesh, distance)
+	faces, sources := removeCollapsedFaces(faces, identity(len(faces)))
+	m := NewIndexedMesh(vertices, faces)
+	m.triangles, m.sources = mesh.Triangles, sources
+	return m
+}
+
+func NewIndexedMesh(vertices []fauxgl.Vector, faces [][3]int) *HalfEdgeMesh {
+	m := &HalfEdgeMesh{Vertices: vertices, Faces: faces}
+	m.HalfEdges = make([]HalfEdge, len(faces)*3)
+	m.outgoing = make([][]int, len(vertices))
+	directed := make(map[edge][]int)
+	undirected := make(map[edge]int)
+	for f, face := range faces {
+		for j := 0; j < 3; j++ {
+			h := 3*f + j
+			a, b := face[j], face[(j+1)%3]
+			m.HalfEdges[h] = HalfEdge{a, f, 3*f + (j+1)%3, -1}
+			m.outgoing[a] = append(m.outgoing[a], h)
+			directed[edge{a, b}] = append(directed[edge{a, b}], h)
+			undirected[sortedEdge(a, b)]++
+		}
+	}
+	for e, hs := range directed {
+		twins := directed[edge{e.B, e.A}]
+		if len(hs) == 1 && len(twins) == 1 && undirected[sortedEdge(e.A, e.B)] == 2 {
+			m.HalfEdges[hs[0]].Twin = twins[0]
+		}
+	}
+	for _, n := range undirected {
+		if n > 2 {
+			m.nonManifoldEdges++
+		}
+	}
+	return m
+}
+
+// triangles of the faces, with the normals, texture coordinates and colors
+// of the triangles they came from; added faces get flat normals
+func (m *HalfEdgeMesh) Mesh() *fauxgl.Mesh {
+	triangles := make([]*fauxgl.Triangle, len(m.Faces))
+	for i, f := range m.Faces {
+		p1, p2, p3 := m.Vertices[f[0]], m.Vertices[f[1]], m.Vertices[f[2]]
+		if i >= len(m.sources) || m.sources[i] < 0 {
+			triangles[i] = fauxgl.NewTriangleForPoints(p1, p2, p3)
+			continue
+		}
+		source := m.triangles[m.sources[i]]
+		if source.Area() == 0 {
+			triangles[i] = fauxgl.NewTriangleForPoints(p1, p2, p3)
+			continue
+		}
+		t := interpolateTriangle(source, p1, p2, p3)
+		// faces turned around by repair keep normals pointing out of them
+		n := t.V1.Normal.Add(t.V2.Normal).Add(t.V3.Normal)
+		if p2.Sub(p1).Cross(p3.Sub(p1)).Dot(n) < 0 {
+			t.V1.Normal = t.V1.Normal.Negate()
+			t.V2.Normal = t.V2.Normal.Negate()
+			t.V3.Normal = t.V3.Normal.Negate()
+		}
+		triangles[i] = t
+	}
+	return fauxgl.NewTriangleMesh(triangles)
+}
+
+func (m *HalfEdgeMesh) Dest(h int) int {
+	return m.HalfEdges[m.HalfEdges[h].Next].Origin
+}
+
+func (m *HalfEdgeMesh) VertexFaces(v int) []int {
+	result := make([]int, len(m.outgoing[v]))
+	for i, h := range m.outgoing[v] {
+		result[i] = m.HalfEdges[h].Face
+	}
+	return result
+}
+
+func (m *HalfEdgeMesh) VertexNeighbors(v int) []int {
+	seen := make(map[int]bool)
+	var result []int
+	add := func(w int) {
+		if !seen[w] {
+			seen[w] = true
+			result = append(result, w)
+		}
+	}
+	for _, h := range m.outgoing[v] {
+		add(m.Dest(h))
+		// the previous vertex of a triangle is the destination of next(next)
+		add(m.Dest(m.HalfEdges[m.HalfEdges[h].Next].Next))
+	}
+	return result
+}
+
+// faces sharing an edge with f across a twin
+func (m *HalfEdgeMesh) FaceNeighbors(f int) []int {
+	var result []int
+	for j := 0; j < 3; j++ {
+		if t := m.HalfEdges[3*f+j].Twin; t >= 0 {
+			result = append(result, m.HalfEdges[t].Face)
+		}
+	}
+	return result
+}
+
+// vertex loops along half-edges without twins
+func (m *HalfEdgeMesh) BoundaryLoops() [][]int {
+	next := make(map[int][]int)
+	var boundary []int
+	for h, e := range m.HalfEdges {
+		if e.Twin < 0 {
+			next[e.Origin] = append(next[e.Origin], h)
+			boundary = append(boundary, h)
+		}
+	}
+	used := make([]bool, len(m.HalfEdges))
+	var result [][]int
+	for _, start := range boundary {
+		if used[start] {
+			continue
+		}
+		var loop []int
+		for h := start; h >= 0 && !used[h]; {
+			used[h] = true
+			loop = append(loop, m.HalfEdges[h].Origin)
+			following := -1
+			for _, k := range next[m.Dest(h)] {
+				if !used[k] {
+					following = k
+					break
+				}
+			}
+			h = following
+		}
+		result = append(result, loop)
+	}
+	return result
+}
+
+func (m *HalfEdgeMesh) BoundaryEdges() int {
+	count := 0
+	for _, e := range m.HalfEdges {
+		if e.Twin < 0 {
+			count++
+		}
+	}
+	return count
+}
+
+func (m *HalfEdgeMesh) NonManifoldEdges() int {
+	return m.nonManifoldEdges
+}
+
+func (m *HalfEdgeMesh) IsClosed() bool {
+	return m.BoundaryEdges() == 0
+}
+
+// every edge has at most two consistently wound faces and the faces
+// around every vertex form a single fan
+func (m *HalfEdgeMesh) IsManifold() bool {
+	for h, e := range m.HalfEdges {
+		if e.Twin < 0 && m.hasOpposite(h) {
+			return false
+		}
+	}
+	if m.nonManifoldEdges > 0 {
+		return false
+	}
+	for v := range m.Vertices {
+		if len(m.outgoing[v]) > 0 && len(m.fan(v)) != len(m.outgoing[v]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *HalfEdgeMesh) EulerCharacteristic() int {
+	edges := make(map[edge]bool)
+	used := make([]bool, len(m.Vertices))
+	for _, f := range m.Faces {
+		for j := 0; j < 3; j++ {
+			edges[sortedEdge(f[j], f[(j+1)%3])] = true
+			used[f[j]] = true
+		}
+	}
+	vertices := 0
+	for _, u := range used {
+		if u {
+			vertices++
+		}
+	}
+	return vertices - len(edges) + len(m.Faces)
+}
+
+// whether another half-edge runs between the same vertices as h
+func (m *HalfEdgeMesh) hasOpposite(h int) bool {
+	a, b := m.HalfEdges[h].Origin, m.Dest(h)
+	for _, k := range m.outgoing[b] {
+		if m.Dest(k) == a {
+			return true
+		}
+	}
+	for _, k := range m.outgoing[a] {
+		if k != h && m.Dest(k) == b {
+			return true
+		}
+	}
+	return false
+}
+
+// outgoing half-edges reachable from the first one by rotating around v
+func (m *HalfEdgeMesh) fan(v int) []int {
+	start := m.outgoing[v][0]
+	seen := map[int]bool{start: true}
+	result := []int{start}
+	// clockwise: twin of the previous half-edge of the face
+	for h := start; ; {
+		prev := m.HalfEdges[m.HalfEdges[h].Next].Next
+		t := m.HalfEdges[prev].Twin
+		if t < 0 || seen[t] {
+			break
+		}
+		seen[t] = true
+		result = append(result, t)
+		h = t
+	}
+	// counterclockwise: next of the twin
+	for h := start; ; {
+		t := m.HalfEdges[h].Twin
+		if t < 0 {
+			break
+		}
+		n := m.HalfEdges[t].Next
+		if seen[n] {
+			break
+		}
+		seen[n] = true
+		result = append(result, n)
+		h = n
+	}
+	return result
+}
+
+func sortedEdge(a, b int) edge {
+	if a > b {
+		a, b = b, a
+	}
+	return edge{a, b}
+}
+
+// drops faces with a repeated vertex, along with their sources
+func removeCollapsedFaces(faces [][3]int, sources []int) ([][3]int, []int) {
+	result := faces[:0]
+	kept := sources[:0]
+	for i, f := range faces {
+		if f[0] != f[1] && f[1] != f[2] && f[2] != f[0] {
+			result = append(result, f)
+			kept = append(kept, sources[i])
+		}
+	}
+	return result, kept
+}
+
+func identity(n int) []int {
+	result := make([]int, n)
+	for i := range result {
+		result[i] = i
+	}
+	return result
+}
@@ -0,0 +1,361 @@
+package choppy
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/fogleman/fauxgl"
+)
+
+type JointShape int
+
+const (
+	// one side of the cross section steps forward and the other back
+	LapJoint JointShape = iota
+	// the cross section inset by a wall thickness sticks out of the back part
+	TongueJoint
+	// a tail widening away from the plane slides along the axis
+	DovetailJoint
+)
+
+type JointOptions struct {
+	Shape JointShape
+
+	// how far the joint reaches in front of the plane
+	Depth float64
+
+	// gap left between the parts where they slide against each other; the
+	// groove of a tongue or dovetail is also this much deeper
+	Clearance float64
+
+	// thickness of the wall around a tongue, measured in from the contour
+	Wall float64
+
+	// direction in the plane the lap step and the dovetail run along; the
+	// zero vector uses the plane's U axis
+	Axis fauxgl.Vector
+
+	// width of the dovetail at the plane and the angle its sides lean out
+	// by, in degrees
+	Width float64
+	Angle float64
+}
+
+func (o *JointOptions) validate() error {
+	if o.Depth <= 0 || o.Clearance < 0 {
+		return errors.New("invalid joint options")
+	}
+	switch o.Shape {
+	case LapJoint:
+	case TongueJoint:
+		if o.Wall <= o.Clearance {
+			return errors.New("joint wall must be thicker than the clearance")
+		}
+	case DovetailJoint:
+		if o.Width <= 0 || o.Angle < 0 || o.Angle >= 90 {
+			return errors.New("invalid dovetail width or angle")
+		}
+	default:
+		return errors.New("unknown joint shape")
+	}
+	return nil
+}
+
+// replaces the flat caps with the joint, cutting each part with a closed
+// mesh of the region behind the plane plus or minus the joint
+func (p cutPlane) chopJoint(m *fauxgl.Mesh, o *JointOptions, report *Report) (front, back *fauxgl.Mesh, err error) {
+	if err := o.validate(); err != nil {
+		return nil, nil, err
+	}
+	polygons, _ := p.capPolygons(p.intersectMesh(m), report)
+	if len(polygons) == 0 {
+		return nil, nil, errors.New("plane does not cut the mesh")
+	}
+	var frontCutter, backCutter *fauxgl.Mesh
+	if o.Shape == TongueJoint {
+		frontCutter, backCutter, err = p.tongueCutters(m, polygons, o)
+	} else {
+		frontCutter, backCutter = p.profileCutters(m, polygons, o)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, cutter := range []*fauxgl.Mesh{frontCutter, backCutter} {
+		for _, t := range cutter.Triangles {
+			t.SetColor(p.capColor)
+		}
+	}
+	if _, front, err = ChopWithMeshTolerance(m, frontCutter, p.tolerance); err != nil {
+		return nil, nil, err
+	}
+	if back, _, err = ChopWithMeshTolerance(m, backCutter, p.tolerance); err != nil {
+		return nil, nil, err
+	}
+	return front, back, nil
+}
+
+// the lap and dovetail are the same across the cross section, so they are
+// a profile across the axis swept along it
+func (p Plane) profileCutters(m *fauxgl.Mesh, polygons []Polygon, o *JointOptions) (front, back *fauxgl.Mesh) {
+	n := p.Normal.Normalize()
+	axis := o.Axis.Sub(n.MulScalar(o.Axis.Dot(n)))
+	if axis.Length() == 0 {
+		axis = p.U
+	}
+	axis = axis.Normalize()
+	box := fauxgl.EmptyBox
+	for _, polygon := range polygons {
+		box = box.Extend(polygon.Exterior.BoundingBox())
+	}
+
+	// U runs across the axis and V points behind the plane
+	q := MakePlaneAlong(p.Unproject(box.Center()), axis, n.Cross(axis))
+	bounds := q.meshBox(m)
+	margin := bounds.Size().Length() * 0.01
+	s0 := bounds.Min.X - margin
+	s1 := bounds.Max.X + margin
+	y := bounds.Max.Y + margin
+	w0 := bounds.Min.Z - margin
+	w1 := bounds.Max.Z + margin
+
+	cutter := func(clearance, depth float64) *fauxgl.Mesh {
+		polygon := Path{{s0, y, 0}}
+		switch o.Shape {
+		case LapJoint:
+			polygon = append(polygon,
+				fauxgl.Vector{s0, -depth, 0},
+				fauxgl.Vector{clearance, -depth, 0},
+				fauxgl.Vector{clearance, 0, 0})
+		case DovetailJoint:
+			// the slanted sides move clearance out square to themselves
+			angle := o.Angle * math.Pi / 180
+			a := o.Width/2 + clearance/math.Cos(angle)
+			b := a + depth*math.Tan(angle)
+			polygon = append(polygon,
+				fauxgl.Vector{s0, 0, 0},
+				fauxgl.Vector{-a, 0, 0},
+				fauxgl.Vector{-b, -depth, 0},
+				fauxgl.Vector{b, -depth, 0},
+				fauxgl.Vector{a, 0, 0})
+		}
+		polygon = append(polygon, fauxgl.Vector{s1, 0, 0}, fauxgl.Vector{s1, y, 0})
+		return q.prism(polygon, w0, w1)
+	}
+	depth := o.Depth
+	if o.Shape == DovetailJoint {
+		depth += o.Clearance
+	}
+	return cutter(o.Clearance, depth), cutter(0, o.Depth)
+}
+
+// the tongue is the cross section inset by the wall, and its groove the
+// cross section inset by the wall less the clearance
+func (p Plane) tongueCutters(m *fauxgl.Mesh, polygons []Polygon, o *JointOptions) (front, back *fauxgl.Mesh, err error) {
+	var tongues, grooves []Polygon
+	for _, polygon := range polygons {
+		tongue, ok := insetPolygon(polygon, o.Wall)
+		if !ok {
+			// too thin for a tongue, so the cap stays flat here
+			continue
+		}
+		groove, _ := insetPolygon(polygon, o.Wall-o.Clearance)
+		tongues = append(tongues, tongue)
+		grooves = append(grooves, groove)
+	}
+	if len(tongues) == 0 || polygonsCross(tongues) || polygonsCross(grooves) {
+		return nil, nil, errors.New("joint wall too thick for the cross section")
+	}
+	box := p.meshBox(m)
+	box = box.Offset(box.Size().Length() * 0.01)
+	front = p.raisedBox(box, grooves, o.Depth+o.Clearance)
+	back = p.raisedBox(box, tongues, o.Depth)
+	return front, back, nil
+}
+
+// box of the mesh in the plane's U, V and normal coordinates
+func (p Plane) meshBox(m *fauxgl.Mesh) fauxgl.Box {
+	normal := p.Normal.Normalize()
+	box := fauxgl.EmptyBox
+	for _, t := range m.Triangles {
+		for _, v := range triangleCorners(t) {
+			q := p.Project(v)
+			q.Z = v.Sub(p.Point).Dot(normal)
+			box = box.Extend(fauxgl.Box{q, q})
+		}
+	}
+	return box
+}
+
+// closed mesh of the box, given in plane coordinates, below the plane and
+// raised to depth in front of it inside the polygons
+func (p Plane) raisedBox(box fauxgl.Box, polygons []Polygon, depth float64) *fauxgl.Mesh {
+	n := p.Normal.Normalize()
+	point := func(q fauxgl.Vector, w float64) fauxgl.Vector {
+		return p.Unproject(q).Add(n.MulScalar(w))
+	}
+	var triangles []*fauxgl.Triangle
+	// counterclockwise in U/V faces against the normal, so faces toward it
+	// are reversed
+	face := func(polygon Polygon, w float64, reverse bool) {
+		points, indexes := triangulate(polygon.paths())
+		for _, t := range indexes {
+			p1 := point(fauxgl.Vector{points[t[0]][0], points[t[0]][1], 0}, w)
+			p2 := point(fauxgl.Vector{points[t[1]][0], points[t[1]][1], 0}, w)
+			p3 := point(fauxgl.Vector{points[t[2]][0], points[t[2]][1], 0}, w)
+			if reverse {
+				p2, p3 = p3, p2
+			}
+			triangles = append(triangles, fauxgl.NewTriangleForPoints(p1, p2, p3))
+		}
+	}
+	// walls of loops with the solid on their left
+	walls := func(path Path, w0, w1 float64) {
+		for i, a := range path {
+			b := path[(i+1)%len(path)]
+			a0, a1 := point(a, w0), point(a, w1)
+			b0, b1 := point(b, w0), point(b, w1)
+			triangles = append(triangles,
+				fauxgl.NewTriangleForPoints(a0, a1, b1),
+				fauxgl.NewTriangleForPoints(a0, b1, b0))
+		}
+	}
+
+	rect := Path{
+		{box.Min.X, box.Min.Y, 0}, {box.Max.X, box.Min.Y, 0},
+		{box.Max.X, box.Max.Y, 0}, {box.Min.X, box.Max.Y, 0}}
+	if rect.SignedArea() < 0 {
+		rect = reversePath(rect)
+	}
+	face(Polygon{rect, nil}, box.Min.Z, false)
+	walls(rect, box.Min.Z, 0)
+
+	// the plane around the polygons, then the polygons raised
+	paths := []Path{rect}
+	for _, polygon := range polygons {
+		for _, path := range polygon.paths() {
+			paths = append(paths, reversePath(path))
+			walls(path, 0, depth)
+		}
+		face(polygon, depth, true)
+	}
+	for _, polygon := range pathsToPolygons(paths) {
+		face(polygon, 0, true)
+	}
+	return fauxgl.NewTriangleMesh(triangles)
+}
+
+// moves every edge of the exterior and interiors distance into the solid,
+// reporting false if nothing is left of the exterior
+func insetPolygon(polygon Polygon, distance float64) (Polygon, bool) {
+	exterior := insetPath(polygon.Exterior, distance)
+	if len(exterior) < 3 || exterior.SignedArea() <= 0 {
+		return Polygon{}, false
+	}
+	var interiors []Path
+	for _, path := range polygon.Interiors {
+		if path = insetPath(path, distance); len(path) >= 3 {
+			interiors = append(interiors, path)
+		}
+	}
+	return Polygon{exterior, interiors}, true
+}
+
+// moves each edge distance to its left, putting each corner where the
+// moved edges meet; edges that turn around once moved are too short to
+// keep, so they are dropped and their neighbors meet instead
+func insetPath(path Path, distance float64) Path {
+	path = removeDuplicatePoints(path)
+	lines := make([]line, len(path))
+	for i, a := range path {
+		d := path[(i+1)%len(path)].Sub(a).Normalize()
+		lines[i] = line{a.Add(leftNormal(d).MulScalar(distance)), d}
+	}
+	for len(lines) >= 3 {
+		n := len(lines)
+		corners := make(Path, n)
+		for i, l := range lines {
+			corners[i] = lines[(i+n-1)%n].intersection(l)
+		}
+		var kept []line
+		for i, l := range lines {
+			if corners[(i+1)%n].Sub(corners[i]).Dot(l.Direction) >= 0 {
+				kept = append(kept, l)
+			}
+		}
+		if len(kept) == n {
+			return removeDuplicatePoints(corners)
+		}
+		lines = kept
+	}
+	return nil
+}
+
+type line struct {
+	Point, Direction fauxgl.Vector
+}
+
+// point where the lines meet, or the start of b where they are parallel
+func (a line) intersection(b line) fauxgl.Vector {
+	cross := a.Direction.X*b.Direction.Y - a.Direction.Y*b.Direction.X
+	if math.Abs(cross) < 1e-12 {
+		return b.Point
+	}
+	d := b.Point.Sub(a.Point)
+	t := (d.X*b.Direction.Y - d.Y*b.Direction.X) / cross
+	return a.Point.Add(a.Direction.MulScalar(t))
+}
+
+func leftNormal(d fauxgl.Vector) fauxgl.Vector {
+	return fauxgl.Vector{-d.Y, d.X, 0}.Normalize()
+}
+
+// drops points equal to the one before them, wrapping around
+func removeDuplicatePoints(path Path) Path {
+	var result Path
+	for i, p := range path {
+		if p != path[(i+len(path)-1)%len(path)] {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+func reversePath(path Path) Path {
+	result := make(Path, len(path))
+	for i, p := range path {
+		result[len(path)-1-i] = p
+	}
+	return result
+}
+
+// reports whether any two edges of the polygons cross
+func polygonsCross(polygons []Polygon) bool {
+	var edges [][2]fauxgl.Vector
+	for _, polygon := range polygons {
+		for _, path := range polygon.paths() {
+			for i, a := range path {
+				b := path[(i+1)%len(path)]
+				if b.X < a.X {
+					a, b = b, a
+				}
+				edges = append(edges, [2]fauxgl.Vector{a, b})
+			}
+		}
+	}
+	// only edges overlapping in X can cross
+	sort.Slice(edges, func(i, j int) bool {
+		return edges[i][0].X < edges[j][0].X
+	})
+	for i, e := range edges {
+		for _, f := range edges[i+1:] {
+			if f[0].X > e[1].X {
+				break
+			}
+			if segmentsIntersect(e[0].X, e[0].Y, e[1].X, e[1].Y, f[0].X, f[0].Y, f[1].X, f[1].Y) {
+				return true
+			}
+		}
+	}
+	return false
+}
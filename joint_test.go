@@ -0,0 +1,63 @@
+package choppy
+
+import (
+	"math"
+	"testing"
+
+	"github.com/fogleman/fauxgl"
+)
+
+func TestChopJoint(t *testing.T) {
+	cube := testBox(fauxgl.Vector{}, fauxgl.Vector{20, 20, 20})
+	sphere := testSphere(fauxgl.Vector{}, 10, 16)
+	z := fauxgl.Vector{0, 0, 1}
+	tongue := &JointOptions{Shape: TongueJoint, Depth: 3, Clearance: 0.2, Wall: 2}
+	lap := &JointOptions{Shape: LapJoint, Depth: 3, Clearance: 0.2}
+	dovetail := &JointOptions{Shape: DovetailJoint, Depth: 3, Clearance: 0.2, Width: 6, Angle: 10}
+	tan := math.Tan(10 * math.Pi / 180)
+	cos := math.Cos(10 * math.Pi / 180)
+	// a bar far from the plane's point with the dovetail running along X,
+	// the axis given off the plane
+	bar := testBox(fauxgl.Vector{2, 100, 0}, fauxgl.Vector{14, 110, 20})
+	along := &JointOptions{Shape: DovetailJoint, Depth: 3, Clearance: 0.2, Width: 6, Angle: 10, Axis: fauxgl.Vector{1, 0, 3}}
+
+	// the equator of the sphere is a regular polygon, and so are its insets
+	const n = 32
+	apothem := 10 * math.Cos(math.Pi/n)
+	inset := func(w float64) float64 {
+		return n * (apothem - w) * (apothem - w) * math.Tan(math.Pi/n)
+	}
+	s := sphere.Volume()
+
+	cases := []struct {
+		name        string
+		mesh        *fauxgl.Mesh
+		plane       Plane
+		joint       *JointOptions
+		front, back float64
+	}{
+		{"tongue at 7.3", cube, MakePlane(fauxgl.Vector{0, 0, 7.3}, z), tongue,
+			8000 - 2920 - 16.4*16.4*3.2, 2920 + 16*16*3},
+		{"tongue at 10", cube, MakePlane(fauxgl.Vector{0, 0, 10}, z), tongue,
+			4000 - 16.4*16.4*3.2, 4000 + 16*16*3},
+		{"lap at 7.3", cube, MakePlane(fauxgl.Vector{0, 0, 7.3}, z), lap,
+			8000 - 2920 - 10.2*20*3, 2920 + 10*20*3},
+		{"lap at 10", cube, MakePlane(fauxgl.Vector{0, 0, 10}, z), lap,
+			4000 - 10.2*20*3, 4000 + 10*20*3},
+		{"dovetail at 10", cube, MakePlane(fauxgl.Vector{0, 0, 10}, z), dovetail,
+			4000 - 20*3.2*(6+0.4/cos+3.2*tan), 4000 + 20*3*(6+3*tan)},
+		{"dovetail off center", bar, MakePlane(fauxgl.Vector{0, 0, 10}, z), along,
+			1200 - 12*3.2*(6+0.4/cos+3.2*tan), 1200 + 12*3*(6+3*tan)},
+		{"tongue through sphere center", sphere, MakePlane(fauxgl.Vector{}, z), tongue,
+			s/2 - inset(1.8)*3.2, s/2 + inset(2)*3},
+	}
+	for _, c := range cases {
+		front, back, err := c.plane.ChopWith(c.mesh, Options{Joint: c.joint})
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+		checkPart(t, c.name+" front", front, c.front, c.mesh.Volume())
+		checkPart(t, c.name+" back", back, c.back, c.mesh.Volume())
+	}
+}
@@ -0,0 +1,183 @@
+package choppy
+
+import (
+	"errors"
+	"math"
+	"strconv"
+
+	"github.com/fogleman/fauxgl"
+)
+
+type LayerOptions struct {
+	Thickness float64
+
+	// registration holes per polygon, shared with the next layer
+	Holes        int
+	HoleDiameter float64
+
+	// extra distance kept between holes, contours and labels
+	Margin float64
+
+	// height of the layer number label, or 0 for none
+	LabelSize float64
+}
+
+type Layer struct {
+	Number int
+
+	// distance of the slice from the lowest point of the mesh along the normal
+	Offset   float64
+	Polygons []Polygon
+
+	// registration hole centers, also cut as interiors of the polygons
+	Holes []fauxgl.Vector
+
+	label fauxgl.Vector
+	box   fauxgl.Box
+	size  float64
+}
+
+// slices the mesh into sheets of the given thickness along normal, cutting
+// each layer where it is halfway through its sheet
+func Layers(mesh *fauxgl.Mesh, normal fauxgl.Vector, options LayerOptions) ([]*Layer, error) {
+	if options.Thickness <= 0 || options.Holes < 0 || options.LabelSize < 0 {
+		return nil, errors.New("invalid layer options")
+	}
+	if options.Holes > 0 && options.HoleDiameter <= 0 {
+		return nil, errors.New("invalid layer options")
+	}
+	normal = normal.Normalize()
+	lo, hi := extent(mesh, normal)
+	n := int(math.Ceil((hi-lo)/options.Thickness - 1e-9))
+
+	var layers []*Layer
+	for i := 0; i < n; i++ {
+		offset := (float64(i) + 0.5) * options.Thickness
+		plane := MakePlane(normal.MulScalar(lo+offset), normal)
+		polygons := Section(mesh, plane)
+		if len(polygons) == 0 {
+			continue
+		}
+		layers = append(layers, &Layer{Number: len(layers) + 1, Offset: offset, Polygons: polygons})
+	}
+
+	box := fauxgl.EmptyBox
+	for _, layer := range layers {
+		box = box.Extend(polygonsBoundingBox(layer.Polygons))
+	}
+	for _, layer := range layers {
+		layer.box = box
+		layer.size = options.LabelSize
+	}
+
+	if options.Holes > 0 {
+		addRegistrationHoles(layers, options)
+	}
+	for _, layer := range layers {
+		layer.placeLabel(options)
+	}
+	return layers, nil
+}
+
+// pins each layer to the next one, keeping the previous hole positions
+// while they still fit both layers
+func addRegistrationHoles(layers []*Layer, options LayerOptions) {
+	hole := &PegOptions{Shape: RoundPeg, Diameter: options.HoleDiameter}
+	r := hole.radius()
+	inset := r + options.Margin
+	spacing := 2*r + options.Margin
+
+	var previous []fauxgl.Vector
+	for i := 0; i+1 < len(layers); i++ {
+		a := layers[i]
+		b := layers[i+1]
+		// previous pins are already holes in a
+		pins := previous
+		for _, p := range previous {
+			if polygonAt(b.Polygons, p, inset) < 0 {
+				pins = nil
+				break
+			}
+		}
+		if pins == nil {
+			accept := func(p fauxgl.Vector) bool {
+				if polygonAt(b.Polygons, p, inset) < 0 {
+					return false
+				}
+				for _, q := range previous {
+					if p.Distance(q) < spacing {
+						return false
+					}
+				}
+				return true
+			}
+			for _, polygon := range a.Polygons {
+				pins = append(pins, pegCenters(polygon, inset, spacing, options.Holes, accept, nil)...)
+			}
+		}
+		a.addHoles(pins, hole)
+		b.addHoles(pins, hole)
+		previous = pins
+	}
+}
+
+func (layer *Layer) addHoles(centers []fauxgl.Vector, hole *PegOptions) {
+	p := &pegs{options: hole}
+	var owners []int
+	for _, c := range centers {
+		if containsVector(layer.Holes, c) {
+			continue
+		}
+		p.centers = append(p.centers, c)
+		owners = append(owners, polygonAt(layer.Polygons, c, 0))
+		layer.Holes = append(layer.Holes, c)
+	}
+	layer.Polygons = p.addHoles(layer.Polygons, owners, 0)
+}
+
+// puts the label in the largest polygon, away from its contours and holes
+func (layer *Layer) placeLabel(options LayerOptions) {
+	if layer.size == 0 {
+		return
+	}
+	var best Polygon
+	for _, polygon := range layer.Polygons {
+		if best.Exterior == nil || polygon.Area() > best.Area() {
+			best = polygon
+		}
+	}
+	text := strconv.Itoa(layer.Number)
+	inset := math.Max(layer.size, 0.6*layer.size*float64(len(text)))/2 + options.Margin
+	accept := func(p fauxgl.Vector) bool { return true }
+	centers := pegCenters(best, inset, 0, 1, accept, nil)
+	if len(centers) == 0 {
+		// too small for the label
+		layer.size = 0
+		return
+	}
+	layer.label = centers[0]
+}
+
+func (layer *Layer) labels() []label {
+	if layer.size == 0 {
+		return nil
+	}
+	return []label{{strconv.Itoa(layer.Number), layer.label, layer.size}}
+}
+
+func (layer *Layer) SaveSVG(path string) error {
+	return saveSVG(path, layer.Polygons, layer.labels(), layer.box)
+}
+
+func (layer *Layer) SaveDXF(path string) error {
+	return saveDXF(path, layer.Polygons, layer.labels())
+}
+
+func containsVector(vectors []fauxgl.Vector, v fauxgl.Vector) bool {
+	for _, w := range vectors {
+		if w == v {
+			return true
+		}
+	}
+	return false
+}
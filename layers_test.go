@@ -0,0 +1,61 @@
+package choppy
+
+import (
+	"testing"
+
+	"github.com/fogleman/fauxgl"
+)
+
+func TestLayers(t *testing.T) {
+	// a cube with a void in its middle third, so the middle layer is a frame
+	mesh := testBox(fauxgl.Vector{}, fauxgl.Vector{30, 30, 30})
+	mesh.Add(testVoid(fauxgl.Vector{10, 10, 10}, fauxgl.Vector{20, 20, 20}))
+	normal := fauxgl.Vector{0, 0, 1}
+	options := LayerOptions{Thickness: 10, Holes: 2, HoleDiameter: 2, Margin: 1}
+	layers, err := Layers(mesh, normal, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(layers) != 3 {
+		t.Fatalf("%d layers, want 3", len(layers))
+	}
+	voids := []int{0, 1, 0}
+	for i, layer := range layers {
+		if layer.Number != i+1 || layer.Offset != 5+10*float64(i) {
+			t.Errorf("layer %d at %g", layer.Number, layer.Offset)
+		}
+		// each hole is cut out of the layer, clear of its contours and void
+		plane := MakePlane(normal.MulScalar(layer.Offset), normal)
+		section := Section(mesh, plane)
+		var interiors int
+		for _, polygon := range layer.Polygons {
+			interiors += len(polygon.Interiors)
+		}
+		if interiors != voids[i]+len(layer.Holes) {
+			t.Errorf("layer %d: %d interiors for %d holes", layer.Number, interiors, len(layer.Holes))
+		}
+		for _, c := range layer.Holes {
+			if polygonAt(section, c, options.HoleDiameter/2+options.Margin) < 0 {
+				t.Errorf("layer %d: hole at %v leaves the layer", layer.Number, c)
+			}
+			if polygonAt(layer.Polygons, c, 0) >= 0 {
+				t.Errorf("layer %d: hole at %v not cut", layer.Number, c)
+			}
+		}
+		// and pins it to the next layer
+		if i+1 < len(layers) {
+			var shared int
+			for _, c := range layer.Holes {
+				if containsVector(layers[i+1].Holes, c) {
+					shared++
+				}
+			}
+			if shared < options.Holes {
+				t.Errorf("layers %d and %d share %d holes, want %d", layer.Number, layer.Number+1, shared, options.Holes)
+			}
+		}
+	}
+	if _, err := Layers(mesh, normal, LayerOptions{Thickness: 10, Holes: 1}); err == nil {
+		t.Error("holes without a diameter: no error")
+	}
+}
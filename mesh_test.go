@@ -0,0 +1,114 @@
+package choppy
+
+import (
+	"math"
+	"testing"
+
+	"github.com/fogleman/fauxgl"
+)
+
+func testBox(min, max fauxgl.Vector) *fauxgl.Mesh {
+	corner := func(i int) fauxgl.Vector {
+		v := min
+		if i&1 != 0 {
+			v.X = max.X
+		}
+		if i&2 != 0 {
+			v.Y = max.Y
+		}
+		if i&4 != 0 {
+			v.Z = max.Z
+		}
+		return v
+	}
+	// outward quads by corner index
+	quads := [][4]int{
+		{0, 2, 3, 1}, {4, 5, 7, 6},
+		{0, 1, 5, 4}, {2, 6, 7, 3},
+		{0, 4, 6, 2}, {1, 3, 7, 5},
+	}
+	var triangles []*fauxgl.Triangle
+	for _, q := range quads {
+		a, b, c, d := corner(q[0]), corner(q[1]), corner(q[2]), corner(q[3])
+		triangles = append(triangles,
+			fauxgl.NewTriangleForPoints(a, b, c),
+			fauxgl.NewTriangleForPoints(a, c, d))
+	}
+	return fauxgl.NewTriangleMesh(triangles)
+}
+
+// box facing inward, bounding a void
+func testVoid(min, max fauxgl.Vector) *fauxgl.Mesh {
+	m := testBox(min, max)
+	for _, t := range m.Triangles {
+		t.V2, t.V3 = t.V3, t.V2
+	}
+	return m
+}
+
+// latitude and longitude sphere with single vertices at its poles
+func testSphere(center fauxgl.Vector, radius float64, n int) *fauxgl.Mesh {
+	point := func(i, j int) fauxgl.Vector {
+		switch i {
+		case 0:
+			return center.Add(fauxgl.Vector{0, 0, radius})
+		case n:
+			return center.Add(fauxgl.Vector{0, 0, -radius})
+		}
+		a := math.Pi * float64(i) / float64(n)
+		b := math.Pi * float64(j%(2*n)) / float64(n)
+		v := fauxgl.Vector{math.Sin(a) * math.Cos(b), math.Sin(a) * math.Sin(b), math.Cos(a)}
+		return center.Add(v.MulScalar(radius))
+	}
+	var triangles []*fauxgl.Triangle
+	for i := 0; i < n; i++ {
+		for j := 0; j < 2*n; j++ {
+			a, b, c, d := point(i, j), point(i+1, j), point(i+1, j+1), point(i, j+1)
+			if i != 0 {
+				triangles = append(triangles, fauxgl.NewTriangleForPoints(a, b, d))
+			}
+			if i != n-1 {
+				triangles = append(triangles, fauxgl.NewTriangleForPoints(b, c, d))
+			}
+		}
+	}
+	return fauxgl.NewTriangleMesh(triangles)
+}
+
+// cylinder along Z from z0 to z1 with caps fanned from center vertices
+func testCylinder(radius, z0, z1 float64, n int) *fauxgl.Mesh {
+	point := func(j int, z float64) fauxgl.Vector {
+		a := 2 * math.Pi * float64(j%n) / float64(n)
+		return fauxgl.Vector{radius * math.Cos(a), radius * math.Sin(a), z}
+	}
+	bottom := fauxgl.Vector{0, 0, z0}
+	top := fauxgl.Vector{0, 0, z1}
+	var triangles []*fauxgl.Triangle
+	for j := 0; j < n; j++ {
+		a0, b0 := point(j, z0), point(j+1, z0)
+		a1, b1 := point(j, z1), point(j+1, z1)
+		triangles = append(triangles,
+			fauxgl.NewTriangleForPoints(a0, b0, b1),
+			fauxgl.NewTriangleForPoints(a0, b1, a1),
+			fauxgl.NewTriangleForPoints(bottom, b0, a0),
+			fauxgl.NewTriangleForPoints(top, a1, b1))
+	}
+	return fauxgl.NewTriangleMesh(triangles)
+}
+
+// fails unless m is closed with the volume, to a part per million of the
+// size of its box
+func checkClosed(t *testing.T, name string, m *fauxgl.Mesh, volume float64) {
+	t.Helper()
+	if m == nil || len(m.Triangles) == 0 {
+		t.Errorf("%s: empty mesh", name)
+		return
+	}
+	if n := openEdges(m.Triangles); n != 0 {
+		t.Errorf("%s: %d open edges", name, n)
+	}
+	size := m.BoundingBox().Size()
+	if got := m.Volume(); math.Abs(got-volume) > 1e-6*math.Max(size.X*size.Y*size.Z, math.Abs(volume)) {
+		t.Errorf("%s: volume %g, want %g", name, got, volume)
+	}
+}
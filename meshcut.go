@@ -0,0 +1,1002 @@
+package choppy
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/fogleman/fauxgl"
+)
+
+var errMeshCut = errors.New("cannot cut the mesh where it touches the cutter")
+
+// splits a closed mesh by a closed cutter into the part inside the cutter
+// and the part outside it, each closed by the surface of the cutter inside
+// the mesh; where faces of the two lie on each other, the part inside keeps
+// the faces of the mesh that face the same way as the cutter's and the part
+// outside those facing the other way
+func ChopWithMesh(mesh, cutter *fauxgl.Mesh) (inside, outside *fauxgl.Mesh, err error) {
+	return ChopWithMeshTolerance(mesh, cutter, 0)
+}
+
+// ChopWithMesh snapping vertices of each mesh closer than the tolerance
+// together; 0 derives it from the size of each mesh
+func ChopWithMeshTolerance(mesh, cutter *fauxgl.Mesh, tolerance float64) (inside, outside *fauxgl.Mesh, err error) {
+	mesh = snapVertices(mesh, tolerance)
+	cutter = snapVertices(cutter, tolerance)
+	// parts of open meshes cannot be checked for holes
+	closed := openEdges(solidTriangles(mesh)) == 0 && openEdges(solidTriangles(cutter)) == 0
+	if tolerance <= 0 {
+		tolerance = math.Max(defaultTolerance(mesh), defaultTolerance(cutter))
+	}
+	return cutMesh(mesh, cutter, tolerance, closed)
+}
+
+// sides of the pieces of one mesh relative to the other
+const (
+	cutOutside = iota
+	cutInside
+	// lying on a face of the other mesh facing the same way
+	cutSame
+	// lying on a face of the other mesh facing the other way
+	cutOpposite
+)
+
+func cutMesh(mesh, cutter *fauxgl.Mesh, tolerance float64, closed bool) (inside, outside *fauxgl.Mesh, err error) {
+	c := newMeshCut(mesh, cutter, tolerance)
+	c.intersect()
+	a, aSources, err := c.pieces(0)
+	if err != nil {
+		return nil, nil, err
+	}
+	b, bSources, err := c.pieces(1)
+	if err != nil {
+		return nil, nil, err
+	}
+	aSides := c.classify(0, a, aSources)
+	bSides := c.classify(1, b, bSources)
+
+	// faces on each other bound the inside once where they face the same
+	// way and the outside where they face apart, and the mesh's faces do
+	inside = fauxgl.NewEmptyMesh()
+	outside = fauxgl.NewEmptyMesh()
+	for i, t := range a {
+		switch aSides[i] {
+		case cutInside, cutSame:
+			inside.Triangles = append(inside.Triangles, t)
+		default:
+			outside.Triangles = append(outside.Triangles, t)
+		}
+	}
+	for i, t := range b {
+		if bSides[i] != cutInside {
+			continue
+		}
+		inside.Triangles = append(inside.Triangles, t)
+		r := *t
+		r.ReverseWinding()
+		outside.Triangles = append(outside.Triangles, &r)
+	}
+	if closed && (openEdges(inside.Triangles) != 0 || openEdges(outside.Triangles) != 0) {
+		return nil, nil, errMeshCut
+	}
+	return inside, outside, nil
+}
+
+// directed edges without a matching edge running the other way
+func openEdges(triangles []*fauxgl.Triangle) int {
+	count := make(map[cutSegment]int)
+	for _, t := range triangles {
+		v := triangleCorners(t)
+		for i := range v {
+			count[cutSegment{v[i], v[(i+1)%3]}]++
+		}
+	}
+	var result int
+	for s, n := range count {
+		if d := n - count[cutSegment{s.B, s.A}]; d > 0 {
+			result += d
+		}
+	}
+	return result
+}
+
+func solidTriangles(mesh *fauxgl.Mesh) []*fauxgl.Triangle {
+	var result []*fauxgl.Triangle
+	for _, t := range mesh.Triangles {
+		if !t.IsDegenerate() {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// copy of the mesh with vertices closer than the tolerance moved to the same
+// position, so that the triangles around each vertex share it exactly; 0
+// uses the default tolerance
+func snapVertices(mesh *fauxgl.Mesh, tolerance float64) *fauxgl.Mesh {
+	if tolerance <= 0 {
+		tolerance = defaultTolerance(mesh)
+	}
+	w := newWelder(tolerance)
+	triangles := make([]*fauxgl.Triangle, len(mesh.Triangles))
+	for i, t := range mesh.Triangles {
+		u := *t
+		u.V1.Position = w.snap(t.V1.Position)
+		u.V2.Position = w.snap(t.V2.Position)
+		u.V3.Position = w.snap(t.V3.Position)
+		triangles[i] = &u
+	}
+	return fauxgl.NewTriangleMesh(triangles)
+}
+
+// point where an edge of one mesh, with its ends in vectorLess order,
+// crosses a triangle of the other; where it passes through an edge of the
+// triangle the key is instead the edge A, B of the mesh and the edge C, D of
+// the cutter, with Mesh and Triangle -1, so both triangles on that edge get
+// the same point
+type crossing struct {
+	Mesh     int
+	A, B     fauxgl.Vector
+	Triangle int
+	C, D     fauxgl.Vector
+}
+
+// segment where a triangle meets a triangle of the other mesh
+type cutSegment struct {
+	A, B fauxgl.Vector
+}
+
+func newCutSegment(a, b fauxgl.Vector) cutSegment {
+	if vectorLess(b, a) {
+		a, b = b, a
+	}
+	return cutSegment{a, b}
+}
+
+// point where two triangles meet, with the edge of the triangle of each
+// mesh it splits, or -1 where it is a corner or inside the triangle
+type cutEnd struct {
+	Point fauxgl.Vector
+	Edges [2]int
+}
+
+// point of a crossing, and the edge of the triangle it crosses that it was
+// moved onto or -1
+type crossingPoint struct {
+	Point fauxgl.Vector
+	Edge  int
+}
+
+type meshCut struct {
+	meshes [2]*fauxgl.Mesh
+	points map[crossing]crossingPoint
+
+	// distance below which crossings snap to the corners, edges and other
+	// crossings near them
+	tolerance float64
+	welder    *welder
+
+	// per mesh, the points splitting each edge, keyed by the edge so that
+	// both triangles on it are split alike, and per triangle the segments
+	// inside it or along its edges
+	edgePoints [2]map[cutSegment][]fauxgl.Vector
+	segments   [2]map[int][]cutSegment
+	cuts       map[cutSegment]bool
+
+	// per mesh and triangle, the triangles of the other mesh lying on it
+	// with an overlap
+	coplanar [2]map[int][]int
+}
+
+func newMeshCut(a, b *fauxgl.Mesh, tolerance float64) *meshCut {
+	c := &meshCut{}
+	c.meshes = [2]*fauxgl.Mesh{a, b}
+	c.tolerance = tolerance
+	c.welder = newWelder(tolerance)
+	c.points = make(map[crossing]crossingPoint)
+	for i := range c.meshes {
+		c.edgePoints[i] = make(map[cutSegment][]fauxgl.Vector)
+		c.segments[i] = make(map[int][]cutSegment)
+		c.coplanar[i] = make(map[int][]int)
+	}
+	c.cuts = make(map[cutSegment]bool)
+	return c
+}
+
+func triangleCorners(t *fauxgl.Triangle) [3]fauxgl.Vector {
+	return [3]fauxgl.Vector{t.V1.Position, t.V2.Position, t.V3.Position}
+}
+
+// finds the segments where triangles of the two meshes meet, testing only
+// pairs in the same cells of a grid over the triangles of the cutter
+func (c *meshCut) intersect() {
+	a, b := c.meshes[0], c.meshes[1]
+	if len(a.Triangles) == 0 || len(b.Triangles) == 0 {
+		return
+	}
+	box := a.BoundingBox()
+	cutterBox := b.BoundingBox()
+	if !box.Intersects(cutterBox) {
+		return
+	}
+
+	// cells about the size of the larger triangles of the two meshes
+	var size float64
+	for _, m := range c.meshes {
+		var total float64
+		for _, t := range m.Triangles {
+			s := t.BoundingBox().Size()
+			total += math.Max(s.X, math.Max(s.Y, s.Z))
+		}
+		size = math.Max(size, total/float64(len(m.Triangles)))
+	}
+	if size <= 0 {
+		return
+	}
+	cellOf := func(p fauxgl.Vector) cell {
+		return cell{int(math.Floor(p.X / size)), int(math.Floor(p.Y / size)), int(math.Floor(p.Z / size))}
+	}
+	cells := func(box fauxgl.Box, fn func(cell)) {
+		c0 := cellOf(box.Min)
+		c1 := cellOf(box.Max)
+		for z := c0.Z; z <= c1.Z; z++ {
+			for y := c0.Y; y <= c1.Y; y++ {
+				for x := c0.X; x <= c1.X; x++ {
+					fn(cell{x, y, z})
+				}
+			}
+		}
+	}
+	grid := make(map[cell][]int)
+	boxes := make([]fauxgl.Box, len(b.Triangles))
+	for j, t := range b.Triangles {
+		boxes[j] = t.BoundingBox()
+		if t.IsDegenerate() || !boxes[j].Intersects(box) {
+			continue
+		}
+		cells(boxes[j], func(k cell) {
+			grid[k] = append(grid[k], j)
+		})
+	}
+
+	seen := make(map[int]int)
+	for i, t := range a.Triangles {
+		tb := t.BoundingBox()
+		if t.IsDegenerate() || !tb.Intersects(cutterBox) {
+			continue
+		}
+		cells(tb, func(k cell) {
+			for _, j := range grid[k] {
+				if seen[j] == i+1 || !tb.Intersects(boxes[j]) {
+					continue
+				}
+				seen[j] = i + 1
+				c.intersectPair(i, j)
+			}
+		})
+	}
+}
+
+// adds the segments where triangle i of the mesh meets triangle j of the
+// cutter, made of the points where the edges of each meet the other;
+// triangles lying on each other meet in the polygon of their overlap, and
+// triangles touching at a point only split the edges through it
+func (c *meshCut) intersectPair(i, j int) {
+	t := [2][3]fauxgl.Vector{
+		triangleCorners(c.meshes[0].Triangles[i]),
+		triangleCorners(c.meshes[1].Triangles[j]),
+	}
+	if separated(t[0], t[1]) || separated(t[1], t[0]) {
+		return
+	}
+	indexes := [2]int{i, j}
+	var ends []cutEnd
+	for m := range t {
+		for k := 0; k < 3; k++ {
+			for _, e := range c.edgeEnds(m, k, t[m], indexes[1-m], t[1-m]) {
+				ends = addEnd(ends, e)
+			}
+		}
+	}
+	for _, e := range ends {
+		for m, k := range e.Edges {
+			if k >= 0 {
+				c.split(m, t[m], k, e.Point)
+			}
+		}
+	}
+	if len(ends) < 2 {
+		return
+	}
+
+	points := make([]fauxgl.Vector, len(ends))
+	for k, e := range ends {
+		points[k] = e.Point
+	}
+	project := triangleProjection(triangleNormal(t[0]))
+	coplanar := true
+	for _, v := range t[0] {
+		coplanar = coplanar && orient3d(t[1][0], t[1][1], t[1][2], v) == 0
+	}
+	if coplanar && !collinear(points, project) {
+		sortAround(points, project)
+		for k := range points {
+			c.addSegment(i, j, points[k], points[(k+1)%len(points)])
+		}
+		c.coplanar[0][i] = append(c.coplanar[0][i], j)
+		c.coplanar[1][j] = append(c.coplanar[1][j], i)
+		return
+	}
+	// otherwise the points lie on a line, where points between the ends
+	// split the segment
+	sortAlong(points)
+	for k := 1; k < len(points); k++ {
+		c.addSegment(i, j, points[k-1], points[k])
+	}
+}
+
+// ends with e added, or merged into the end at the same point
+func addEnd(ends []cutEnd, e cutEnd) []cutEnd {
+	for k := range ends {
+		if ends[k].Point != e.Point {
+			continue
+		}
+		for m, edge := range e.Edges {
+			if edge >= 0 {
+				ends[k].Edges[m] = edge
+			}
+		}
+		return ends
+	}
+	return append(ends, e)
+}
+
+func (c *meshCut) addSegment(i, j int, a, b fauxgl.Vector) {
+	s := newCutSegment(a, b)
+	c.segments[0][i] = append(c.segments[0][i], s)
+	c.segments[1][j] = append(c.segments[1][j], s)
+	c.cuts[s] = true
+}
+
+// records p as splitting edge k of triangle t of mesh m
+func (c *meshCut) split(m int, t [3]fauxgl.Vector, k int, p fauxgl.Vector) {
+	key := newCutSegment(t[k], t[(k+1)%3])
+	if p == key.A || p == key.B {
+		// snapped to a corner
+		return
+	}
+	if !containsVector(c.edgePoints[m][key], p) {
+		c.edgePoints[m][key] = append(c.edgePoints[m][key], p)
+	}
+}
+
+// reports whether all corners of a are strictly on one side of b
+func separated(a, b [3]fauxgl.Vector) bool {
+	s1 := orient3d(b[0], b[1], b[2], a[0])
+	s2 := orient3d(b[0], b[1], b[2], a[1])
+	s3 := orient3d(b[0], b[1], b[2], a[2])
+	return s1 == s2 && s2 == s3 && s1 != 0
+}
+
+func triangleNormal(t [3]fauxgl.Vector) fauxgl.Vector {
+	return t[1].Sub(t[0]).Cross(t[2].Sub(t[0]))
+}
+
+// points where edge k of triangle t of mesh m meets triangle o, with index
+// j, of the other mesh: where it crosses o, or its ends lying on o, and
+// where it crosses the edges of o if it lies in the plane of o
+func (c *meshCut) edgeEnds(m, k int, t [3]fauxgl.Vector, j int, o [3]fauxgl.Vector) []cutEnd {
+	p, q := t[k], t[(k+1)%3]
+	sp := orient3d(o[0], o[1], o[2], p)
+	sq := orient3d(o[0], o[1], o[2], q)
+	end := func(v fauxgl.Vector, edge, other int) cutEnd {
+		var e cutEnd
+		e.Point = v
+		e.Edges[m] = edge
+		e.Edges[1-m] = other
+		return e
+	}
+	var ends []cutEnd
+	switch {
+	case sp == 0 && sq == 0:
+		project := triangleProjection(triangleNormal(o))
+		for _, v := range [2]fauxgl.Vector{p, q} {
+			if edge, ok := locate(v, o, project); ok {
+				ends = append(ends, end(v, -1, edge))
+			}
+		}
+		// corners of o on the edge are ends found from the edges of o
+		for l := 0; l < 3; l++ {
+			r, s := o[l], o[(l+1)%3]
+			if edgesCross(p, q, r, s, project) {
+				ends = append(ends, end(c.edgeCrossing(m, p, q, r, s), k, l))
+			}
+		}
+	case sp == 0 || sq == 0:
+		v := p
+		if sp != 0 {
+			v = q
+		}
+		if edge, ok := locate(v, o, triangleProjection(triangleNormal(o))); ok {
+			ends = append(ends, end(v, -1, edge))
+		}
+	case sp != sq:
+		if v, edge, ok := c.faceCrossing(m, p, q, j, o); ok {
+			ends = append(ends, end(v, k, edge))
+		}
+	}
+	return ends
+}
+
+// point where edge p, q of a triangle of mesh m, with its ends on opposite
+// sides of triangle t (index j) of the other mesh, crosses t, and the edge
+// of t it passes through or -1; through a corner of t it is the corner, and
+// every triangle sharing an edge gets the same point
+func (c *meshCut) faceCrossing(m int, p, q fauxgl.Vector, j int, t [3]fauxgl.Vector) (fauxgl.Vector, int, bool) {
+	if vectorLess(q, p) {
+		p, q = q, p
+	}
+	s := [3]int{
+		orient3d(p, q, t[0], t[1]),
+		orient3d(p, q, t[1], t[2]),
+		orient3d(p, q, t[2], t[0]),
+	}
+	edge, other := -1, -1
+	sign := 0
+	for k, x := range s {
+		switch {
+		case x == 0 && edge < 0:
+			edge = k
+		case x == 0:
+			other = k
+		case sign == 0:
+			sign = x
+		case x != sign:
+			return fauxgl.Vector{}, -1, false
+		}
+	}
+	if other >= 0 {
+		// through the corner the two edges share
+		if edge == 0 && other == 2 {
+			return t[0], -1, true
+		}
+		return t[other], -1, true
+	}
+	if edge >= 0 {
+		return c.edgeCrossing(m, p, q, t[edge], t[(edge+1)%3]), edge, true
+	}
+
+	key := crossing{m, p, q, j, fauxgl.Vector{}, fauxgl.Vector{}}
+	if x, ok := c.points[key]; ok {
+		return x.Point, x.Edge, true
+	}
+	n := triangleNormal(t)
+	dp := p.Sub(t[0]).Dot(n)
+	dq := q.Sub(t[0]).Dot(n)
+	v := p.Add(q.Sub(p).MulScalar(dp / (dp - dq)))
+	// a crossing next to an edge of t splits the edge instead of leaving a
+	// sliver beside it
+	edge = -1
+	for k := 0; k < 3; k++ {
+		if w := segmentPoint(v, t[k], t[(k+1)%3]); w.Distance(v) <= c.tolerance {
+			v, edge = w, k
+			break
+		}
+	}
+	v = c.snap(v, p, q, t[0], t[1], t[2])
+	if v == t[0] || v == t[1] || v == t[2] {
+		edge = -1
+	}
+	c.points[key] = crossingPoint{v, edge}
+	return v, edge, true
+}
+
+// point where edge p, q of mesh m crosses edge r, s of the other mesh,
+// computed from the edges in a fixed order so it does not depend on which
+// edge crosses which
+func (c *meshCut) edgeCrossing(m int, p, q, r, s fauxgl.Vector) fauxgl.Vector {
+	if vectorLess(q, p) {
+		p, q = q, p
+	}
+	if vectorLess(s, r) {
+		r, s = s, r
+	}
+	key := crossing{-1, p, q, -1, r, s}
+	if m == 1 {
+		key = crossing{-1, r, s, -1, p, q}
+	}
+	if x, ok := c.points[key]; ok {
+		return x.Point
+	}
+	a, b := key.A, key.B
+	d := key.D.Sub(key.C)
+	n := b.Sub(a).Cross(d)
+	u := key.C.Sub(a).Cross(d).Dot(n) / n.Dot(n)
+	v := c.snap(a.Add(b.Sub(a).MulScalar(u)), key.A, key.B, key.C, key.D)
+	c.points[key] = crossingPoint{v, -1}
+	return v
+}
+
+// the nearest of the corners closer to v than the tolerance, or else the
+// first crossing seen that is, so that crossings next to each other do not
+// leave slivers between them
+func (c *meshCut) snap(v fauxgl.Vector, corners ...fauxgl.Vector) fauxgl.Vector {
+	result := v
+	best := c.tolerance
+	for _, p := range corners {
+		if d := p.Distance(v); d <= best {
+			result, best = p, d
+		}
+	}
+	if result != v {
+		c.welder.add(result)
+		return result
+	}
+	return c.welder.snap(v)
+}
+
+// edge of t that p, lying in the plane of t, splits, or -1 where p is a
+// corner or inside t; false where p is outside t
+func locate(p fauxgl.Vector, t [3]fauxgl.Vector, project func(fauxgl.Vector) fauxgl.Vector) (int, bool) {
+	for _, v := range t {
+		if p == v {
+			return -1, true
+		}
+	}
+	q := project(p)
+	edge := -1
+	for k := 0; k < 3; k++ {
+		a, b := project(t[k]), project(t[(k+1)%3])
+		d := orient2d(a.X, a.Y, b.X, b.Y, q.X, q.Y)
+		if d < 0 {
+			return -1, false
+		}
+		if d == 0 {
+			edge = k
+		}
+	}
+	return edge, true
+}
+
+// reports whether segments p, q and r, s, lying in one plane, cross at a
+// point inside both
+func edgesCross(p, q, r, s fauxgl.Vector, project func(fauxgl.Vector) fauxgl.Vector) bool {
+	a, b, c, d := project(p), project(q), project(r), project(s)
+	opposite := func(x, y float64) bool {
+		return x > 0 && y < 0 || x < 0 && y > 0
+	}
+	return opposite(orient2d(c.X, c.Y, d.X, d.Y, a.X, a.Y), orient2d(c.X, c.Y, d.X, d.Y, b.X, b.Y)) &&
+		opposite(orient2d(a.X, a.Y, b.X, b.Y, c.X, c.Y), orient2d(a.X, a.Y, b.X, b.Y, d.X, d.Y))
+}
+
+// reports whether points lying in one plane lie on a line
+func collinear(points []fauxgl.Vector, project func(fauxgl.Vector) fauxgl.Vector) bool {
+	a := project(points[0])
+	var b fauxgl.Vector
+	for _, p := range points[1:] {
+		if q := project(p); q.Distance(a) > b.Distance(a) {
+			b = q
+		}
+	}
+	for _, p := range points[1:] {
+		q := project(p)
+		if orient2d(a.X, a.Y, b.X, b.Y, q.X, q.Y) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// sorts the corners of a convex polygon lying in one plane around it
+func sortAround(points []fauxgl.Vector, project func(fauxgl.Vector) fauxgl.Vector) {
+	var center fauxgl.Vector
+	for _, p := range points {
+		center = center.Add(project(p))
+	}
+	center = center.DivScalar(float64(len(points)))
+	angles := make(map[fauxgl.Vector]float64, len(points))
+	for _, p := range points {
+		q := project(p).Sub(center)
+		angles[p] = math.Atan2(q.Y, q.X)
+	}
+	sort.Slice(points, func(i, j int) bool {
+		return angles[points[i]] < angles[points[j]]
+	})
+}
+
+// sorts points lying on a line along it
+func sortAlong(points []fauxgl.Vector) {
+	box := fauxgl.Box{points[0], points[0]}
+	for _, p := range points {
+		box = box.Extend(fauxgl.Box{p, p})
+	}
+	s := box.Size()
+	axis := func(p fauxgl.Vector) float64 {
+		switch {
+		case s.X >= s.Y && s.X >= s.Z:
+			return p.X
+		case s.Y >= s.Z:
+			return p.Y
+		}
+		return p.Z
+	}
+	sort.Slice(points, func(i, j int) bool {
+		return axis(points[i]) < axis(points[j])
+	})
+}
+
+// triangles of mesh m with the triangles meeting the other mesh split along
+// the segments and at the points on their edges, and the triangle of the
+// mesh each came from; parts of edges lying on segments are added to the
+// cuts
+func (c *meshCut) pieces(m int) ([]*fauxgl.Triangle, []int, error) {
+	var result []*fauxgl.Triangle
+	var sources []int
+	for i, t := range c.meshes[m].Triangles {
+		if t.IsDegenerate() {
+			continue
+		}
+		corners := triangleCorners(t)
+		var points [3][]fauxgl.Vector
+		split := false
+		for k := range corners {
+			points[k] = c.edgePoints[m][newCutSegment(corners[k], corners[(k+1)%3])]
+			split = split || len(points[k]) > 0
+		}
+		segments := c.segments[m][i]
+		if !split && len(segments) == 0 {
+			result = append(result, t)
+			sources = append(sources, i)
+			continue
+		}
+		triangles, cuts, err := splitTriangle(t, points, segments)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, s := range cuts {
+			c.cuts[s] = true
+		}
+		for range triangles {
+			sources = append(sources, i)
+		}
+		result = append(result, triangles...)
+	}
+	return result, sources, nil
+}
+
+// splits a triangle into the faces of the planar graph made of its edges,
+// split at the given points, and the segments, and triangulates each face;
+// segments along an edge are not added again but returned as the parts of
+// the edge they cover; fails unless the faces cover the triangle
+func splitTriangle(t *fauxgl.Triangle, edgePoints [3][]fauxgl.Vector, segments []cutSegment) ([]*fauxgl.Triangle, []cutSegment, error) {
+	corners := triangleCorners(t)
+	project := triangleProjection(t.Normal())
+
+	var vertices []fauxgl.Vector
+	lookup := make(map[fauxgl.Vector]int)
+	index := func(p fauxgl.Vector) int {
+		i, ok := lookup[p]
+		if !ok {
+			i = len(vertices)
+			lookup[p] = i
+			vertices = append(vertices, p)
+		}
+		return i
+	}
+	edges := make(map[edge]bool)
+	addEdge := func(a, b int) {
+		if a != b {
+			edges[sortedEdge(a, b)] = true
+		}
+	}
+	// the vertices along each edge in order
+	var chains [3][]int
+	for k := 0; k < 3; k++ {
+		p, q := corners[k], corners[(k+1)%3]
+		d := q.Sub(p)
+		ps := append([]fauxgl.Vector(nil), edgePoints[k]...)
+		sort.Slice(ps, func(i, j int) bool {
+			return ps[i].Sub(p).Dot(d) < ps[j].Sub(p).Dot(d)
+		})
+		chains[k] = append(chains[k], index(p))
+		for _, v := range ps {
+			chains[k] = append(chains[k], index(v))
+		}
+		chains[k] = append(chains[k], index(q))
+		for i := 1; i < len(chains[k]); i++ {
+			addEdge(chains[k][i-1], chains[k][i])
+		}
+	}
+	var cuts []cutSegment
+	for _, s := range segments {
+		a, b := index(s.A), index(s.B)
+		along := false
+		for _, chain := range chains {
+			i, j := indexOf(chain, a), indexOf(chain, b)
+			if i < 0 || j < 0 {
+				continue
+			}
+			if i > j {
+				i, j = j, i
+			}
+			for k := i; k < j; k++ {
+				cuts = append(cuts, newCutSegment(vertices[chain[k]], vertices[chain[k+1]]))
+			}
+			along = true
+			break
+		}
+		if !along {
+			addEdge(a, b)
+		}
+	}
+
+	uv := make([]fauxgl.Vector, len(vertices))
+	for i, v := range vertices {
+		uv[i] = project(v)
+	}
+	faces, holes := traceFaces(uv, edges)
+
+	var result []*fauxgl.Triangle
+	var area float64
+	for f, face := range faces {
+		loops := append([][]int{face}, holes[f]...)
+		var paths []Path
+		var ids []int
+		for _, loop := range loops {
+			path := make(Path, len(loop))
+			for i, v := range loop {
+				path[i] = uv[v]
+			}
+			paths = append(paths, path)
+			ids = append(ids, loop...)
+		}
+		_, triangles := triangulate(paths)
+		for _, tri := range triangles {
+			if int(tri[0]) >= len(ids) || int(tri[1]) >= len(ids) || int(tri[2]) >= len(ids) {
+				return nil, nil, errMeshCut
+			}
+			p1 := vertices[ids[tri[0]]]
+			p2 := vertices[ids[tri[1]]]
+			p3 := vertices[ids[tri[2]]]
+			u := interpolateTriangle(t, p1, p2, p3)
+			area += u.Area()
+			result = append(result, u)
+		}
+	}
+	if math.Abs(area-t.Area()) > 1e-6*t.Area() {
+		return nil, nil, errMeshCut
+	}
+	return result, cuts, nil
+}
+
+// maps points of a plane with the given normal to two coordinates, dropping
+// the largest component of the normal and keeping counterclockwise turns
+// seen from the front counterclockwise
+func triangleProjection(n fauxgl.Vector) func(fauxgl.Vector) fauxgl.Vector {
+	a := n.Abs()
+	switch {
+	case a.X >= a.Y && a.X >= a.Z:
+		if n.X > 0 {
+			return func(p fauxgl.Vector) fauxgl.Vector { return fauxgl.Vector{p.Y, p.Z, 0} }
+		}
+		return func(p fauxgl.Vector) fauxgl.Vector { return fauxgl.Vector{p.Z, p.Y, 0} }
+	case a.Y >= a.Z:
+		if n.Y > 0 {
+			return func(p fauxgl.Vector) fauxgl.Vector { return fauxgl.Vector{p.Z, p.X, 0} }
+		}
+		return func(p fauxgl.Vector) fauxgl.Vector { return fauxgl.Vector{p.X, p.Z, 0} }
+	}
+	if n.Z > 0 {
+		return func(p fauxgl.Vector) fauxgl.Vector { return fauxgl.Vector{p.X, p.Y, 0} }
+	}
+	return func(p fauxgl.Vector) fauxgl.Vector { return fauxgl.Vector{p.Y, p.X, 0} }
+}
+
+// counterclockwise faces of a planar graph, each with the clockwise loops
+// of the parts of the graph lying inside it as holes
+func traceFaces(points []fauxgl.Vector, edges map[edge]bool) ([][]int, [][][]int) {
+	neighbors := make([][]int, len(points))
+	for e := range edges {
+		neighbors[e.A] = append(neighbors[e.A], e.B)
+		neighbors[e.B] = append(neighbors[e.B], e.A)
+	}
+	for i, ns := range neighbors {
+		p := points[i]
+		angles := make(map[int]float64, len(ns))
+		for _, j := range ns {
+			angles[j] = math.Atan2(points[j].Y-p.Y, points[j].X-p.X)
+		}
+		sort.Slice(ns, func(a, b int) bool {
+			return angles[ns[a]] < angles[ns[b]]
+		})
+	}
+
+	// walk each directed edge once keeping the face on the left, turning
+	// to the edge just clockwise of the one coming back
+	visited := make(map[edge]bool)
+	var faces, loops [][]int
+	for u, ns := range neighbors {
+		for _, v := range ns {
+			if visited[edge{u, v}] {
+				continue
+			}
+			var face []int
+			a, b := u, v
+			for !visited[edge{a, b}] {
+				visited[edge{a, b}] = true
+				face = append(face, a)
+				around := neighbors[b]
+				k := 0
+				for around[k] != a {
+					k++
+				}
+				a, b = b, around[(k+len(around)-1)%len(around)]
+			}
+			if loopArea(points, face) > 0 {
+				faces = append(faces, face)
+			} else {
+				loops = append(loops, face)
+			}
+		}
+	}
+
+	// the outer loop of each connected part is a hole of the smallest face
+	// around it, and the outer loop of the triangle is around nothing
+	holes := make([][][]int, len(faces))
+	for _, loop := range loops {
+		p := points[loop[0]]
+		best := -1
+		var bestArea float64
+		for f, face := range faces {
+			if containsIndex(face, loop[0]) {
+				continue
+			}
+			path := make(Path, len(face))
+			for i, v := range face {
+				path[i] = points[v]
+			}
+			area := path.Area()
+			if (best < 0 || area < bestArea) && path.ContainsPoint(p) {
+				best = f
+				bestArea = area
+			}
+		}
+		if best >= 0 {
+			holes[best] = append(holes[best], loop)
+		}
+	}
+	return faces, holes
+}
+
+func loopArea(points []fauxgl.Vector, loop []int) float64 {
+	path := make(Path, len(loop))
+	for i, v := range loop {
+		path[i] = points[v]
+	}
+	return path.SignedArea()
+}
+
+func containsIndex(a []int, x int) bool {
+	return indexOf(a, x) >= 0
+}
+
+func indexOf(a []int, x int) int {
+	for i, y := range a {
+		if x == y {
+			return i
+		}
+	}
+	return -1
+}
+
+// triangle at the given points of t with its vertex attributes
+// interpolated
+func interpolateTriangle(t *fauxgl.Triangle, p1, p2, p3 fauxgl.Vector) *fauxgl.Triangle {
+	a, b, c := t.V1.Position, t.V2.Position, t.V3.Position
+	v1 := fauxgl.InterpolateVertexes(t.V1, t.V2, t.V3, fauxgl.Barycentric(a, b, c, p1))
+	v2 := fauxgl.InterpolateVertexes(t.V1, t.V2, t.V3, fauxgl.Barycentric(a, b, c, p2))
+	v3 := fauxgl.InterpolateVertexes(t.V1, t.V2, t.V3, fauxgl.Barycentric(a, b, c, p3))
+	v1.Position = p1
+	v2.Position = p2
+	v3.Position = p3
+	return fauxgl.NewTriangle(v1, v2, v3)
+}
+
+var classifyDirections = []fauxgl.Vector{
+	rayDirection,
+	fauxgl.Vector{-0.6123, 0.3421, 0.7127}.Normalize(),
+	fauxgl.Vector{0.2718, -0.8314, 0.4843}.Normalize(),
+}
+
+// side of each piece of mesh m, split from the triangles in sources,
+// relative to the other mesh; triangles sharing an edge that is not a cut
+// are on the same side, so only one point of each such region is tested
+// against the other mesh
+func (c *meshCut) classify(m int, triangles []*fauxgl.Triangle, sources []int) []int {
+	parents := make([]int, len(triangles))
+	for i := range parents {
+		parents[i] = i
+	}
+	find := func(i int) int {
+		for parents[i] != i {
+			parents[i] = parents[parents[i]]
+			i = parents[i]
+		}
+		return i
+	}
+	owners := make(map[cutSegment]int)
+	for i, t := range triangles {
+		corners := triangleCorners(t)
+		for k := 0; k < 3; k++ {
+			s := newCutSegment(corners[k], corners[(k+1)%3])
+			if c.cuts[s] {
+				continue
+			}
+			if j, ok := owners[s]; ok {
+				parents[find(i)] = find(j)
+			} else {
+				owners[s] = i
+			}
+		}
+	}
+
+	// test the centroid of the largest triangle of each region
+	representatives := make(map[int]int)
+	for i, t := range triangles {
+		r := find(i)
+		if j, ok := representatives[r]; !ok || t.Area() > triangles[j].Area() {
+			representatives[r] = i
+		}
+	}
+	other := c.meshes[1-m]
+	sides := make(map[int]int)
+	for r, i := range representatives {
+		t := triangles[i]
+		p := t.V1.Position.Add(t.V2.Position).Add(t.V3.Position).DivScalar(3)
+		if side, ok := c.onSurface(m, sources[i], p); ok {
+			sides[r] = side
+			continue
+		}
+		// a ray through an edge of the other mesh counts it twice or not at
+		// all, so the majority of three rays decides
+		var votes int
+		for _, d := range classifyDirections {
+			inside := false
+			for _, u := range other.Triangles {
+				if rayHitsTriangle(p, d, u.V1.Position, u.V2.Position, u.V3.Position) {
+					inside = !inside
+				}
+			}
+			if inside {
+				votes++
+			}
+		}
+		if votes >= 2 {
+			sides[r] = cutInside
+		}
+	}
+	result := make([]int, len(triangles))
+	for i := range triangles {
+		result[i] = sides[find(i)]
+	}
+	return result
+}
+
+// side of a point p of triangle i of mesh m lying on a triangle of the
+// other mesh, by whether the two face the same way
+func (c *meshCut) onSurface(m, i int, p fauxgl.Vector) (int, bool) {
+	n := triangleNormal(triangleCorners(c.meshes[m].Triangles[i]))
+	for _, j := range c.coplanar[m][i] {
+		o := triangleCorners(c.meshes[1-m].Triangles[j])
+		no := triangleNormal(o)
+		if _, ok := locate(p, o, triangleProjection(no)); !ok {
+			continue
+		}
+		if n.Dot(no) > 0 {
+			return cutSame, true
+		}
+		return cutOpposite, true
+	}
+	return cutOutside, false
+}
@@ -0,0 +1,104 @@
+package choppy
+
+import (
+	"math"
+	"testing"
+
+	"github.com/fogleman/fauxgl"
+)
+
+// fails unless both parts are closed with the given volumes
+func checkCut(t *testing.T, name string, inside, outside *fauxgl.Mesh, volume, total float64) {
+	t.Helper()
+	checkPart(t, name+" inside", inside, volume, total)
+	checkPart(t, name+" outside", outside, total-volume, total)
+}
+
+func checkPart(t *testing.T, name string, m *fauxgl.Mesh, volume, total float64) {
+	t.Helper()
+	if n := openEdges(m.Triangles); n != 0 {
+		t.Errorf("%s: %d open edges", name, n)
+	}
+	if got := m.Volume(); math.Abs(got-volume) > 1e-9*total {
+		t.Errorf("%s: volume %g, want %g", name, got, volume)
+	}
+}
+
+func TestChopWithMesh(t *testing.T) {
+	cube := testBox(fauxgl.Vector{}, fauxgl.Vector{20, 20, 20})
+	sphere := testSphere(fauxgl.Vector{10, 10, 10}, 8, 12)
+	cases := []struct {
+		name   string
+		mesh   *fauxgl.Mesh
+		cutter *fauxgl.Mesh
+		volume float64
+	}{
+		{"corner", cube, testBox(fauxgl.Vector{5.3, 5.7, -1}, fauxgl.Vector{24.1, 23.9, 9.2}), 14.7 * 14.3 * 9.2},
+		{"inside", cube, testBox(fauxgl.Vector{5, 5, 5}, fauxgl.Vector{15, 15, 15}), 1000},
+		// faces lying on the faces of the cube
+		{"coplanar", cube, testBox(fauxgl.Vector{5, 5, 0}, fauxgl.Vector{15, 15, 20}), 2000},
+		{"flush", cube, testBox(fauxgl.Vector{10, -5, -5}, fauxgl.Vector{25, 25, 25}), 4000},
+		// vertices of the cutter on faces and edges of the cube
+		{"cube vertices", cube, testBox(fauxgl.Vector{10, 10, 10}, fauxgl.Vector{30, 30, 30}), 1000},
+		{"sphere pole", cube, testSphere(fauxgl.Vector{10, 10, 12}, 8, 12), sphere.Volume()},
+		{"sphere", cube, sphere, sphere.Volume()},
+		{"cylinder fan centers", testCylinder(10, 0, 20, 24), testBox(fauxgl.Vector{0, -20, -5}, fauxgl.Vector{20, 20, 25}), testCylinder(10, 0, 20, 24).Volume() / 2},
+	}
+	for _, c := range cases {
+		inside, outside, err := ChopWithMesh(c.mesh, c.cutter)
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+		checkCut(t, c.name, inside, outside, c.volume, c.mesh.Volume())
+	}
+}
+
+func TestChopWithMeshContacts(t *testing.T) {
+	cube := testBox(fauxgl.Vector{}, fauxgl.Vector{20, 20, 20})
+	// a wedge along X with its ridge lying in the top face of the cube,
+	// its cross section given in (z, y)
+	along := MakePlaneAlong(fauxgl.Vector{}, fauxgl.Vector{1, 0, 0}, fauxgl.Vector{0, 0, 1})
+	wedge := along.prism(Path{{20, 10, 0}, {5, -5, 0}, {5, 25, 0}}, -5, 25)
+	cases := []struct {
+		name            string
+		cutter          *fauxgl.Mesh
+		volume          float64
+		inside, outside float64
+	}{
+		// faces lying on faces of the cube and facing the same way
+		{"flush", testBox(fauxgl.Vector{10, 0, 0}, fauxgl.Vector{20, 20, 20}), 4000, 1600, 1600},
+		{"coplanar top and bottom", testBox(fauxgl.Vector{5, 5, 0}, fauxgl.Vector{15, 15, 20}), 2000, 1000, 3000},
+		// faces lying on faces of the cube and facing the other way
+		{"face to face", testBox(fauxgl.Vector{20, 0, 0}, fauxgl.Vector{30, 20, 20}), 0, 0, 2400},
+		{"face to face overlapping", testBox(fauxgl.Vector{20, 5, 5}, fauxgl.Vector{30, 25, 25}), 0, 0, 2400},
+		// only an edge or a corner on the surface of the cube
+		{"edge on a face", wedge, 4000, 1000 + 400*math.Sqrt2, 2200 + 400*math.Sqrt2},
+		{"corner on a face", testBox(fauxgl.Vector{10, 10, 20}, fauxgl.Vector{30, 30, 40}), 0, 0, 2400},
+	}
+	for _, c := range cases {
+		inside, outside, err := ChopWithMesh(cube, c.cutter)
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+		checkCut(t, c.name, inside, outside, c.volume, 8000)
+		for _, p := range []struct {
+			name string
+			mesh *fauxgl.Mesh
+			area float64
+		}{{"inside", inside, c.inside}, {"outside", outside, c.outside}} {
+			// faces left between the cutter and the cube would add area,
+			// and slivers next to them small triangles
+			if a := p.mesh.SurfaceArea(); math.Abs(a-p.area) > 1e-9*2400 {
+				t.Errorf("%s %s: area %g, want %g", c.name, p.name, a, p.area)
+			}
+			for _, u := range p.mesh.Triangles {
+				if u.Area() < 1e-6*p.area {
+					t.Errorf("%s %s: sliver of area %g", c.name, p.name, u.Area())
+					break
+				}
+			}
+		}
+	}
+}
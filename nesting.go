@@ -0,0 +1,139 @@
+package choppy
+
+import (
+	"math"
+	"sort"
+
+	"github.com/fogleman/fauxgl"
+)
+
+// containment tree of closed paths that do not cross each other
+type pathTree struct {
+	paths   []Path
+	parents []int // index of the innermost path containing each path, or -1
+	depths  []int // number of paths containing each path
+	order   []int // indexes from largest to smallest, so parents come first
+	boxes   []fauxgl.Box
+}
+
+func newPathTree(paths []Path) *pathTree {
+	n := len(paths)
+	tree := &pathTree{paths, make([]int, n), make([]int, n), nil, make([]fauxgl.Box, n)}
+	if n == 0 {
+		return tree
+	}
+	areas := make([]float64, n)
+	bounds := fauxgl.EmptyBox
+	for i, path := range paths {
+		tree.boxes[i] = path.BoundingBox()
+		areas[i] = path.Area()
+		bounds = bounds.Extend(tree.boxes[i])
+	}
+
+	// a path can only be contained by larger ones, so insert from largest to
+	// smallest and look for the parent among the paths already in the grid
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return areas[order[i]] > areas[order[j]]
+	})
+	tree.order = order
+
+	grid := newBoxGrid(bounds, n)
+	for _, i := range order {
+		tree.parents[i] = -1
+		// paths do not cross, so one point strictly inside a path tells
+		// whether another path contains it
+		p, ok := paths[i].HolePoint()
+		if !ok {
+			p = paths[i][0]
+		}
+		cell := grid.cell(p)
+		// cells list paths from largest to smallest, so the first container
+		// found from the back is the innermost one
+		for k := len(cell) - 1; k >= 0; k-- {
+			j := cell[k]
+			if tree.contains(j, i, p) {
+				tree.parents[i] = j
+				tree.depths[i] = tree.depths[j] + 1
+				break
+			}
+		}
+		grid.insert(i, tree.boxes[i])
+	}
+	return tree
+}
+
+// reports whether path a contains every point of path b
+// whether path a contains path b, given a point inside b
+func (tree *pathTree) contains(a, b int, p fauxgl.Vector) bool {
+	box := tree.boxes[a]
+	if !box.Contains(tree.boxes[b].Min) || !box.Contains(tree.boxes[b].Max) {
+		return false
+	}
+	return tree.paths[a].ContainsPoint(p)
+}
+
+func (tree *pathTree) maxDepth() int {
+	var result int
+	for _, d := range tree.depths {
+		if d+1 > result {
+			result = d + 1
+		}
+	}
+	return result
+}
+
+// uniform grid listing the boxes overlapping each cell in insertion order
+type boxGrid struct {
+	bounds fauxgl.Box
+	nx, ny int
+	cells  [][]int
+}
+
+func newBoxGrid(bounds fauxgl.Box, count int) *boxGrid {
+	n := int(math.Ceil(math.Sqrt(float64(count))))
+	return &boxGrid{bounds, n, n, make([][]int, n*n)}
+}
+
+func (g *boxGrid) index(p fauxgl.Vector) (int, int) {
+	size := g.bounds.Size()
+	x, y := 0, 0
+	if size.X > 0 {
+		x = int((p.X - g.bounds.Min.X) / size.X * float64(g.nx))
+	}
+	if size.Y > 0 {
+		y = int((p.Y - g.bounds.Min.Y) / size.Y * float64(g.ny))
+	}
+	x = clampInt(x, 0, g.nx-1)
+	y = clampInt(y, 0, g.ny-1)
+	return x, y
+}
+
+func (g *boxGrid) insert(i int, box fauxgl.Box) {
+	x0, y0 := g.index(box.Min)
+	x1, y1 := g.index(box.Max)
+	for y := y0; y <= y1; y++ {
+		for x := x0; x <= x1; x++ {
+			c := y*g.nx + x
+			g.cells[c] = append(g.cells[c], i)
+		}
+	}
+}
+
+func (g *boxGrid) cell(p fauxgl.Vector) []int {
+	x, y := g.index(p)
+	return g.cells[y*g.nx+x]
+}
+
+func clampInt(x, lo, hi int) int {
+	if x < lo {
+		return lo
+	}
+	if x > hi {
+		return hi
+	}
+	return x
+}
@@ -0,0 +1,79 @@
+package choppy
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/fogleman/fauxgl"
+)
+
+func SaveOBJ(path string, mesh *fauxgl.Mesh) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return WriteOBJ(file, mesh)
+}
+
+// writes triangles with their normals and texture coordinates, and vertex
+// colors after the positions if any vertex has one
+func WriteOBJ(w io.Writer, mesh *fauxgl.Mesh) error {
+	colored := false
+	for _, t := range mesh.Triangles {
+		zero := fauxgl.Color{}
+		if t.V1.Color != zero || t.V2.Color != zero || t.V3.Color != zero {
+			colored = true
+			break
+		}
+	}
+
+	type position struct {
+		Position fauxgl.Vector
+		Color    fauxgl.Color
+	}
+	positions := make(map[position]int)
+	textures := make(map[fauxgl.Vector]int)
+	normals := make(map[fauxgl.Vector]int)
+	bw := bufio.NewWriter(w)
+	index := func(v fauxgl.Vertex) string {
+		p := position{v.Position, v.Color}
+		if !colored {
+			p.Color = fauxgl.Color{}
+		}
+		i, ok := positions[p]
+		if !ok {
+			i = len(positions) + 1
+			positions[p] = i
+			q := p.Position
+			if colored {
+				c := p.Color
+				fmt.Fprintf(bw, "v %g %g %g %g %g %g\n", q.X, q.Y, q.Z, c.R, c.G, c.B)
+			} else {
+				fmt.Fprintf(bw, "v %g %g %g\n", q.X, q.Y, q.Z)
+			}
+		}
+		j, ok := textures[v.Texture]
+		if !ok {
+			j = len(textures) + 1
+			textures[v.Texture] = j
+			fmt.Fprintf(bw, "vt %g %g\n", v.Texture.X, v.Texture.Y)
+		}
+		k, ok := normals[v.Normal]
+		if !ok {
+			k = len(normals) + 1
+			normals[v.Normal] = k
+			fmt.Fprintf(bw, "vn %g %g %g\n", v.Normal.X, v.Normal.Y, v.Normal.Z)
+		}
+		return fmt.Sprintf("%d/%d/%d", i, j, k)
+	}
+	for _, t := range mesh.Triangles {
+		f1 := index(t.V1)
+		f2 := index(t.V2)
+		f3 := index(t.V3)
+		fmt.Fprintf(bw, "f %s %s %s\n", f1, f2, f3)
+	}
+	return bw.Flush()
+}
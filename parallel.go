@@ -0,0 +1,42 @@
+package choppy
+
+import (
+	"runtime"
+	"sync"
+)
+
+// number of chunks to split count items into, one per cpu
+func workers(count int) int {
+	wn := runtime.NumCPU()
+	if wn > count {
+		wn = count
+	}
+	if wn < 1 {
+		wn = 1
+	}
+	return wn
+}
+
+// splits [0, count) into wn contiguous chunks and runs fn on each chunk
+// concurrently; wi is the chunk number, so results collected per chunk can
+// be merged in order
+func parallel(count, wn int, fn func(wi, i0, i1 int)) {
+	var wg sync.WaitGroup
+	for wi := 0; wi < wn; wi++ {
+		wg.Add(1)
+		go func(wi int) {
+			n := count / wn
+			if count%wn > 0 {
+				n++
+			}
+			i0 := n * wi
+			i1 := i0 + n
+			if i1 > count {
+				i1 = count
+			}
+			fn(wi, i0, i1)
+			wg.Done()
+		}(wi)
+	}
+	wg.Wait()
+}
@@ -2,6 +2,7 @@ package choppy
 
 import (
 	"math"
+	"sort"
 
 	"github.com/fogleman/fauxgl"
 )
@@ -26,34 +27,76 @@ func (a Path) BoundingBox() fauxgl.Box {
 	return fauxgl.Box{fauxgl.Vector{x0, y0, z0}, fauxgl.Vector{x1, y1, z1}}
 }
 
-// func (a Path) SignedArea() float64 {
-// 	var result float64
-// 	for i, p1 := range a {
-// 		p2 := a[(i+1)%len(a)]
-// 		result += p1.X*p2.Y - p2.X*p1.Y
-// 	}
-// 	return result / 2
-// }
+// positive for counterclockwise paths; summed relative to the first point
+// so thin loops far from the origin keep their sign
+func (a Path) SignedArea() float64 {
+	var result float64
+	for i := 1; i+1 < len(a); i++ {
+		p1 := a[i].Sub(a[0])
+		p2 := a[i+1].Sub(a[0])
+		result += p1.X*p2.Y - p2.X*p1.Y
+	}
+	return result / 2
+}
+
+func (a Path) Area() float64 {
+	return math.Abs(a.SignedArea())
+}
+
+func (a Path) Perimeter() float64 {
+	var result float64
+	for i, p1 := range a {
+		p2 := a[(i+1)%len(a)]
+		result += p1.Distance(p2)
+	}
+	return result
+}
 
+// holes wind clockwise in the plane's coordinates, opposite to the faces
+// bounding the part
 func (a Path) IsHole() bool {
-	_, ok := a.HolePoint()
-	return ok
+	return a.SignedArea() < 0
 }
 
+// point strictly inside the path whatever its winding, for the triangulator
+// to find holes by; it is the middle of the widest span inside the path on
+// a scanline halfway across the largest gap between vertex heights, so the
+// scanline passes through no vertex
 func (a Path) HolePoint() (fauxgl.Vector, bool) {
-	p1 := a[0]
-	p2 := a[1]
-	mid := p1.Add(p2).DivScalar(2)
-	dir := p1.Sub(p2).Perpendicular().Normalize()
-	off := 1.0
-	for i := 0; i < 32; i++ {
-		p := mid.Add(dir.MulScalar(off))
-		if a.ContainsPoint(p) {
-			return p, true
+	ys := make([]float64, len(a))
+	for i, p := range a {
+		ys[i] = p.Y
+	}
+	sort.Float64s(ys)
+	var y, gap float64
+	for i := 1; i < len(ys); i++ {
+		if d := ys[i] - ys[i-1]; d > gap {
+			y = (ys[i-1] + ys[i]) / 2
+			gap = d
+		}
+	}
+	if gap == 0 {
+		return fauxgl.Vector{}, false
+	}
+	var xs []float64
+	for i, v := range a {
+		w := a[(i+1)%len(a)]
+		if (v.Y > y) != (w.Y > y) {
+			xs = append(xs, v.X+(y-v.Y)*(w.X-v.X)/(w.Y-v.Y))
+		}
+	}
+	sort.Float64s(xs)
+	var x, width float64
+	for i := 1; i < len(xs); i += 2 {
+		if d := xs[i] - xs[i-1]; d > width {
+			x = (xs[i-1] + xs[i]) / 2
+			width = d
 		}
-		off /= 2
 	}
-	return fauxgl.Vector{}, false
+	if width == 0 {
+		return fauxgl.Vector{}, false
+	}
+	return fauxgl.Vector{x, y, a[0].Z}, true
 }
 
 func (a Path) Project(plane Plane) Path {
@@ -73,29 +116,33 @@ func (a Path) ContainsPath(b Path) bool {
 	return true
 }
 
+// crossing test along +x, counting edges whose lower end is at or below p
+// and upper end above it so rays through vertices count once; points on
+// the boundary may go either way; points outside the bounding box cross
+// nothing so it is not checked first
 func (a Path) ContainsPoint(p fauxgl.Vector) bool {
-	box := a.BoundingBox()
-	if !box.Contains(p) {
-		return false
-	}
-	outside := box.Min.Sub(fauxgl.Vector{1, 1, 1})
-	v1x1 := p.X
-	v1y1 := p.Y
-	v1x2 := outside.X
-	v1y2 := outside.Y
-	n := len(a)
-	count := 0
-	for i := 0; i < n; i++ {
-		j := (i + 1) % n
-		v2x1 := a[i].X
-		v2y1 := a[i].Y
-		v2x2 := a[j].X
-		v2y2 := a[j].Y
-		if segmentsIntersect(v1x1, v1y1, v1x2, v1y2, v2x1, v2y1, v2x2, v2y2) {
-			count++
+	inside := false
+	for i, v := range a {
+		w := a[(i+1)%len(a)]
+		up := w.Y > p.Y
+		if (v.Y > p.Y) == up {
+			continue
+		}
+		o := orient2d(v.X, v.Y, w.X, w.Y, p.X, p.Y)
+		if o != 0 && (o > 0) == up {
+			inside = !inside
 		}
 	}
-	return count%2 == 1
+	return inside
+}
+
+func (a Path) BoundaryDistance(p fauxgl.Vector) float64 {
+	result := math.Inf(1)
+	for i, v := range a {
+		w := a[(i+1)%len(a)]
+		result = math.Min(result, segmentDistance(p, v, w))
+	}
+	return result
 }
 
 func projectPaths(paths []Path, plane Plane) []Path {
@@ -106,16 +153,37 @@ func projectPaths(paths []Path, plane Plane) []Path {
 	return result
 }
 
-func joinPaths(paths []Path) []Path {
+func joinPaths(paths []Path) (closed, open []Path, dropped int) {
 	lookup := make(map[fauxgl.Vector]Path, len(paths))
+	ends := make(map[fauxgl.Vector]bool, len(paths))
 	for _, path := range paths {
 		lookup[path[0]] = path
+		ends[path[len(path)-1]] = true
+	}
+	// walk open chains from their first point so they are reported whole,
+	// visiting paths in input order so the result is deterministic
+	var starts []fauxgl.Vector
+	for _, path := range paths {
+		if !ends[path[0]] {
+			starts = append(starts, path[0])
+		}
 	}
-	var result []Path
+	next := 0
 	for len(lookup) > 0 {
 		var v fauxgl.Vector
-		for v = range lookup {
-			break
+		if len(starts) > 0 {
+			v = starts[0]
+			starts = starts[1:]
+			if _, ok := lookup[v]; !ok {
+				continue
+			}
+		} else {
+			for ; ; next++ {
+				if _, ok := lookup[paths[next][0]]; ok {
+					v = paths[next][0]
+					break
+				}
+			}
 		}
 		var path Path
 		for {
@@ -128,13 +196,15 @@ func joinPaths(paths []Path) []Path {
 			}
 		}
 		if path[0] != path[len(path)-1] {
+			open = append(open, path)
 			continue
 		}
 		path = path[1:]
 		if len(path) < 3 {
+			dropped++
 			continue
 		}
-		result = append(result, path)
+		closed = append(closed, path)
 	}
-	return result
+	return
 }
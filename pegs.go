@@ -0,0 +1,249 @@
+package choppy
+
+import (
+	"errors"
+	"math"
+
+	"github.com/fogleman/fauxgl"
+)
+
+type PegShape int
+
+const (
+	RoundPeg PegShape = iota
+	SquarePeg
+	KeyedPeg
+)
+
+type PegOptions struct {
+	Shape     PegShape
+	Diameter  float64
+	Depth     float64
+	Clearance float64
+
+	// number of pegs per cross section polygon
+	Count int
+
+	// extra distance kept between sockets and contours, holes, other pegs
+	// and the far side of the part
+	Margin float64
+}
+
+type pegs struct {
+	options     *PegOptions
+	centers     []fauxgl.Vector
+	frontOwners []int
+	backOwners  []int
+}
+
+// places pegs in the front polygons where sockets also fit in the back
+// polygons, and where the back part, depth(p) thick behind the cap point p,
+// is deep enough to hold them
+func (o *PegOptions) place(front, back []Polygon, depth func(fauxgl.Vector) float64) (*pegs, error) {
+	if o.Diameter <= 0 || o.Depth <= 0 || o.Clearance < 0 || o.Count < 1 {
+		return nil, errors.New("invalid peg options")
+	}
+	r := o.radius() + o.Clearance
+	inset := r + o.Margin
+	result := &pegs{options: o}
+	shallow := 0
+	deep := func(p fauxgl.Vector) bool {
+		need := o.Depth + o.Clearance + o.Margin
+		for _, q := range append(o.path(p, o.Clearance), p) {
+			if depth(q) <= need {
+				shallow++
+				return false
+			}
+		}
+		return true
+	}
+	for i, polygon := range front {
+		accept := func(p fauxgl.Vector) bool {
+			return polygonAt(back, p, inset) >= 0
+		}
+		for _, p := range pegCenters(polygon, inset, 2*r+o.Margin, o.Count, accept, deep) {
+			result.centers = append(result.centers, p)
+			result.frontOwners = append(result.frontOwners, i)
+			result.backOwners = append(result.backOwners, polygonAt(back, p, inset))
+		}
+	}
+	if len(result.centers) == 0 {
+		if shallow > 0 {
+			return nil, errors.New("part too thin behind the cap for the sockets")
+		}
+		return nil, errors.New("no room for pegs in cross section")
+	}
+	return result, nil
+}
+
+// radius of the circle enclosing the peg
+func (o *PegOptions) radius() float64 {
+	if o.Shape == SquarePeg {
+		return o.Diameter / math.Sqrt2
+	}
+	return o.Diameter / 2
+}
+
+// clockwise outline of a peg grown by offset
+func (o *PegOptions) path(center fauxgl.Vector, offset float64) Path {
+	r := o.Diameter/2 + offset
+	var path Path
+	if o.Shape == SquarePeg {
+		corners := [][2]float64{{1, 1}, {1, -1}, {-1, -1}, {-1, 1}}
+		for _, c := range corners {
+			path = append(path, center.Add(fauxgl.Vector{c[0] * r, c[1] * r, 0}))
+		}
+		return path
+	}
+	const n = 32
+	flat := o.Diameter/2*0.6 + offset
+	for i := 0; i < n; i++ {
+		a := -2 * math.Pi * float64(i) / n
+		x := math.Cos(a) * r
+		y := math.Sin(a) * r
+		if o.Shape == KeyedPeg {
+			x = math.Min(x, flat)
+		}
+		path = append(path, center.Add(fauxgl.Vector{x, y, 0}))
+	}
+	return path
+}
+
+// index of the polygon containing p at least inset from its boundary, or -1
+func polygonAt(polygons []Polygon, p fauxgl.Vector, inset float64) int {
+	for i, polygon := range polygons {
+		if polygon.ContainsPoint(p) && polygon.BoundaryDistance(p) >= inset {
+			return i
+		}
+	}
+	return -1
+}
+
+// picks up to count centers spread over the polygon; accept filters the grid
+// of candidates, and deep, which is slower and may be nil, checks each one
+// about to be picked
+func pegCenters(polygon Polygon, inset, spacing float64, count int, accept, deep func(fauxgl.Vector) bool) []fauxgl.Vector {
+	box := polygon.Exterior.BoundingBox()
+	size := box.Size()
+	step := math.Max(size.X, size.Y) / 64
+	if step <= 0 {
+		return nil
+	}
+
+	// candidates on a grid that keep enough distance from the boundary
+	var candidates []fauxgl.Vector
+	var distances []float64
+	for y := box.Min.Y + step/2; y < box.Max.Y; y += step {
+		for x := box.Min.X + step/2; x < box.Max.X; x += step {
+			p := fauxgl.Vector{x, y, 0}
+			if !polygon.ContainsPoint(p) {
+				continue
+			}
+			d := polygon.BoundaryDistance(p)
+			if d < inset || !accept(p) {
+				continue
+			}
+			candidates = append(candidates, p)
+			distances = append(distances, d)
+		}
+	}
+
+	// start farthest from the boundary, then spread out from chosen pegs
+	var result []fauxgl.Vector
+	for len(result) < count {
+		best := -1
+		var bestScore float64
+		for i, p := range candidates {
+			score := distances[i]
+			if len(result) > 0 {
+				score = math.Inf(1)
+				for _, q := range result {
+					score = math.Min(score, p.Distance(q))
+				}
+				if score < spacing {
+					continue
+				}
+			}
+			if best < 0 || score > bestScore {
+				best = i
+				bestScore = score
+			}
+		}
+		if best < 0 {
+			break
+		}
+		if deep != nil && !deep(candidates[best]) {
+			last := len(candidates) - 1
+			candidates[best], distances[best] = candidates[last], distances[last]
+			candidates, distances = candidates[:last], distances[:last]
+			continue
+		}
+		result = append(result, candidates[best])
+	}
+	return result
+}
+
+// distance from the cap point q to the far side of the mesh behind the plane
+func (p Plane) depthBehind(m *fauxgl.Mesh, q fauxgl.Vector) float64 {
+	o := p.Unproject(q)
+	d := p.Normal.Normalize().Negate()
+	result := math.Inf(1)
+	for _, t := range m.Triangles {
+		if s, ok := rayTriangle(o, d, t.V1.Position, t.V2.Position, t.V3.Position); ok {
+			result = math.Min(result, s)
+		}
+	}
+	return result
+}
+
+func (pegs *pegs) addHoles(polygons []Polygon, owners []int, offset float64) []Polygon {
+	result := make([]Polygon, len(polygons))
+	for i, polygon := range polygons {
+		interiors := make([]Path, len(polygon.Interiors))
+		copy(interiors, polygon.Interiors)
+		result[i] = Polygon{polygon.Exterior, interiors}
+	}
+	for i, center := range pegs.centers {
+		polygon := &result[owners[i]]
+		path := pegs.options.path(center, offset)
+		polygon.Interiors = append(polygon.Interiors, path)
+	}
+	return result
+}
+
+func (pegs *pegs) pegMesh(plane cutPlane) *fauxgl.Mesh {
+	o := pegs.options
+	return pegs.mesh(plane, o.Depth, 0, 1)
+}
+
+func (pegs *pegs) socketMesh(plane cutPlane) *fauxgl.Mesh {
+	o := pegs.options
+	return pegs.mesh(plane, o.Depth+o.Clearance, o.Clearance, -1)
+}
+
+// extrudes each outline from the plane to depth behind it, facing outward
+// for pegs (sign = 1) or inward for sockets (sign = -1)
+func (pegs *pegs) mesh(plane cutPlane, depth, offset, sign float64) *fauxgl.Mesh {
+	n := plane.Normal.Normalize()
+	down := n.MulScalar(-depth)
+	var triangles []*fauxgl.Triangle
+	for _, center := range pegs.centers {
+		path := pegs.options.path(center, offset)
+		c := plane.Unproject(center)
+		for i, a := range path {
+			b := path[(i+1)%len(path)]
+			a0 := plane.capPoint(a)
+			b0 := plane.capPoint(b)
+			a1 := a0.Add(down)
+			b1 := b0.Add(down)
+			out := a0.Add(b0).DivScalar(2).Sub(c).MulScalar(sign)
+			triangles = append(triangles, newTriangleFacing(a0, b0, b1, out))
+			triangles = append(triangles, newTriangleFacing(a0, b1, a1, out))
+			triangles = append(triangles, newTriangleFacing(c.Add(down), a1, b1, n.MulScalar(-sign)))
+		}
+	}
+	for _, t := range triangles {
+		t.SetColor(plane.capColor)
+	}
+	return fauxgl.NewTriangleMesh(triangles)
+}
@@ -0,0 +1,33 @@
+package choppy
+
+import (
+	"math"
+	"testing"
+
+	"github.com/fogleman/fauxgl"
+)
+
+func TestPegs(t *testing.T) {
+	slab := testBox(fauxgl.Vector{}, fauxgl.Vector{20, 20, 10})
+	for _, shape := range []PegShape{RoundPeg, SquarePeg, KeyedPeg} {
+		o := &PegOptions{shape, 4, 3, 0.2, 1, 1}
+		plane := MakePlane(fauxgl.Vector{0, 0, 6}, fauxgl.Vector{0, 0, 1})
+		front, back, err := plane.ChopWith(slab, Options{Pegs: o})
+		if err != nil {
+			t.Errorf("peg shape %d: %v", shape, err)
+			continue
+		}
+		// a peg fills its outline to the depth, a socket its grown outline
+		// to the depth and clearance
+		peg := math.Abs(o.path(fauxgl.Vector{}, 0).SignedArea()) * o.Depth
+		socket := math.Abs(o.path(fauxgl.Vector{}, o.Clearance).SignedArea()) * (o.Depth + o.Clearance)
+		checkClosed(t, "peg", front, 1600+peg)
+		checkClosed(t, "socket", back, 2400-socket)
+
+		// 4 behind the cap is too thin for a socket 3.2 deep with a margin of 1
+		plane = MakePlane(fauxgl.Vector{0, 0, 4}, fauxgl.Vector{0, 0, 1})
+		if _, _, err := plane.ChopWith(slab, Options{Pegs: o}); err == nil {
+			t.Errorf("peg shape %d: sockets punch through the back part", shape)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package choppy
 
 import (
+	"errors"
+	"math"
+
 	"github.com/fogleman/fauxgl"
 )
 
@@ -10,12 +13,57 @@ type Plane struct {
 	U, V   fauxgl.Vector
 }
 
+// plane of a cut, with the distance below which intersections snap to mesh
+// vertices and the vertex color of the faces added to close it
+type cutPlane struct {
+	Plane
+	tolerance float64
+	capColor  fauxgl.Color
+}
+
 func MakePlane(point, normal fauxgl.Vector) Plane {
 	u := normal.Perpendicular().Normalize()
 	v := u.Cross(normal).Normalize()
 	return Plane{point, normal, u, v}
 }
 
+// plane whose U axis points along u, made perpendicular to the normal; u
+// along the normal gives the axes of MakePlane
+func MakePlaneAlong(point, normal, u fauxgl.Vector) Plane {
+	w := u.Sub(normal.MulScalar(u.Dot(normal) / normal.Dot(normal)))
+	if !(w.Length() > 1e-9*u.Length()) {
+		return MakePlane(point, normal)
+	}
+	u = w.Normalize()
+	v := u.Cross(normal).Normalize()
+	return Plane{point, normal, u, v}
+}
+
+// plane at offset from the lowest point of the mesh along normal
+func PlaneAtOffset(mesh *fauxgl.Mesh, normal fauxgl.Vector, offset float64) Plane {
+	normal = normal.Normalize()
+	lo, _ := extent(mesh, normal)
+	return MakePlane(normal.MulScalar(lo+offset), normal)
+}
+
+func (p Plane) Negate() Plane {
+	return MakePlane(p.Point, p.Normal.Negate())
+}
+
+// the plane cutting m with the tolerance and cap color of the options; a
+// zero tolerance is derived from the size of the mesh
+func (p Plane) cut(m *fauxgl.Mesh, o Options) cutPlane {
+	tolerance := o.Tolerance
+	if tolerance <= 0 {
+		tolerance = defaultTolerance(m)
+	}
+	return cutPlane{p, tolerance, o.CapColor}
+}
+
+func (p cutPlane) Negate() cutPlane {
+	return cutPlane{p.Plane.Negate(), p.tolerance, p.capColor}
+}
+
 func (p Plane) Project(point fauxgl.Vector) fauxgl.Vector {
 	d := point.Sub(p.Point)
 	x := d.Dot(p.U)
@@ -27,52 +75,522 @@ func (p Plane) Unproject(point fauxgl.Vector) fauxgl.Vector {
 	return p.Point.Add(p.U.MulScalar(point.X)).Add(p.V.MulScalar(point.Y))
 }
 
+func (p Plane) Chop(m *fauxgl.Mesh) *fauxgl.Mesh {
+	return p.chop(m, nil)
+}
+
+// welded front part with the cap vertices shared by the clipped walls
+func (p Plane) ChopIndexed(m *fauxgl.Mesh) *HalfEdgeMesh {
+	mesh := p.Chop(m)
+	distance := defaultTolerance(m)
+	vertices, faces, _ := weldVertices(mesh, distance)
+	faces, sources := removeCollapsedFaces(faces, identity(len(faces)))
+	faces, sources = splitTJunctions(vertices, faces, sources, distance)
+	result := NewIndexedMesh(vertices, faces)
+	result.triangles, result.sources = mesh.Triangles, sources
+	return result
+}
+
+func (p Plane) ChopReport(m *fauxgl.Mesh) (*fauxgl.Mesh, *Report) {
+	report := &Report{}
+	mesh := p.chop(m, report)
+	return mesh, report
+}
+
+func (p Plane) chop(m *fauxgl.Mesh, report *Report) *fauxgl.Mesh {
+	return p.cut(m, Options{}).chop(m, report)
+}
+
+func (p cutPlane) chop(m *fauxgl.Mesh, report *Report) *fauxgl.Mesh {
+	report.countDegenerate(m)
+	segments := p.intersectMesh(m)
+	mesh := p.weldWalls(p.clipMesh(m), segments)
+	polygons, points := p.capPolygons(segments, report)
+	mesh.Add(p.capMesh(polygons, points, report))
+	return mesh
+}
+
+// both parts from one pass over the triangles, sharing the cap
+// triangulation unless vertices on the plane make the caps differ; ChopWith
+// with only a tolerance in the options makes the same cut
+func (p Plane) ChopBoth(m *fauxgl.Mesh) (*fauxgl.Mesh, *fauxgl.Mesh) {
+	return p.chopBoth(m, nil)
+}
+
+// ChopBoth with a report on the front cap, which also counts the open
+// chains, dropped loops and empty caps of the back cap where it differs
+func (p Plane) ChopBothReport(m *fauxgl.Mesh) (*fauxgl.Mesh, *fauxgl.Mesh, *Report) {
+	report := &Report{}
+	front, back := p.chopBoth(m, report)
+	return front, back, report
+}
+
+func (p Plane) chopBoth(m *fauxgl.Mesh, report *Report) (*fauxgl.Mesh, *fauxgl.Mesh) {
+	c := p.cut(m, Options{})
+	report.countDegenerate(m)
+	s := c.splitMesh(m)
+	front, back := s.Front, s.Back
+	polygons, points := c.capPolygons(s.FrontSegments, report)
+	sliced := c.capMesh(polygons, points, report)
+	front.Add(sliced)
+	if s.Touches {
+		behind := report.back()
+		polygons, points = c.capPolygons(s.BackSegments, behind)
+		sliced = c.capMesh(polygons, points, behind)
+		report.addBack(behind)
+	} else {
+		sliced = sliced.Copy()
+	}
+	sliced.ReverseWinding()
+	back.Add(sliced)
+	return front, back
+}
+
+func (p Plane) ChopWith(m *fauxgl.Mesh, options Options) (*fauxgl.Mesh, *fauxgl.Mesh, error) {
+	return p.chopWith(m, options, nil)
+}
+
+// ChopWith with a report on the front cap, which also counts the open
+// chains, dropped loops and empty caps of the back cap where it differs
+func (p Plane) ChopWithReport(m *fauxgl.Mesh, options Options) (*fauxgl.Mesh, *fauxgl.Mesh, *Report, error) {
+	report := &Report{}
+	front, back, err := p.chopWith(m, options, report)
+	return front, back, report, err
+}
+
+func (p Plane) chopWith(m *fauxgl.Mesh, options Options, report *Report) (*fauxgl.Mesh, *fauxgl.Mesh, error) {
+	if options.Kerf < 0 {
+		return nil, nil, errors.New("kerf must not be negative")
+	}
+	if options.Joint != nil && (options.Pegs != nil || options.Kerf != 0 || options.KerfOffset != 0) {
+		return nil, nil, errors.New("joints cannot be combined with pegs or a kerf")
+	}
+	c := p.cut(m, options)
+	report.countDegenerate(m)
+	if options.Joint != nil {
+		return c.chopJoint(m, options.Joint, report)
+	}
+
+	// the front and back parts are bounded by two planes when cutting a kerf
+	fp, bp := c, c
+	fp.Plane, bp.Plane = p.kerfPlanes(options)
+
+	var front, back *fauxgl.Mesh
+	var frontCap, backCap []Polygon
+	var frontPoints, backPoints map[fauxgl.Vector]fauxgl.Vector
+	behind := report.back()
+	if fp == bp {
+		s := fp.splitMesh(m)
+		front, back = s.Front, s.Back
+		frontCap, frontPoints = fp.capPolygons(s.FrontSegments, report)
+		backCap, backPoints = frontCap, frontPoints
+		if s.Touches {
+			backCap, backPoints = fp.capPolygons(s.BackSegments, behind)
+		}
+	} else {
+		frontSegments := fp.intersectMesh(m)
+		var backSegments []Path
+		if bp.touches(m) {
+			backSegments = bp.behindSegments(m)
+		} else {
+			backSegments = bp.intersectMesh(m)
+		}
+		front = fp.weldWalls(fp.clipMesh(m), frontSegments)
+		back = bp.weldWalls(bp.Negate().clipMesh(m), backSegments)
+		frontCap, frontPoints = fp.capPolygons(frontSegments, report)
+		backCap, backPoints = bp.capPolygons(backSegments, behind)
+	}
+
+	if options.Pegs != nil {
+		depth := func(q fauxgl.Vector) float64 {
+			return bp.depthBehind(m, q)
+		}
+		pegs, err := options.Pegs.place(frontCap, backCap, depth)
+		if err != nil {
+			return nil, nil, err
+		}
+		frontCap = pegs.addHoles(frontCap, pegs.frontOwners, 0)
+		backCap = pegs.addHoles(backCap, pegs.backOwners, options.Pegs.Clearance)
+		front.Add(pegs.pegMesh(fp))
+		back.Add(pegs.socketMesh(bp))
+	}
+
+	front.Add(fp.capMesh(frontCap, frontPoints, report))
+	triangles := bp.capMesh(backCap, backPoints, behind)
+	triangles.ReverseWinding()
+	back.Add(triangles)
+	report.addBack(behind)
+	return front, back, nil
+}
+
+// planes bounding the front and back parts, moved apart by the kerf around
+// the kerf offset; both are the plane itself without a kerf
+func (p Plane) kerfPlanes(options Options) (front, back Plane) {
+	front, back = p, p
+	if options.Kerf != 0 || options.KerfOffset != 0 {
+		n := p.Normal.Normalize()
+		front.Point = p.Point.Add(n.MulScalar(options.KerfOffset + options.Kerf/2))
+		back.Point = p.Point.Add(n.MulScalar(options.KerfOffset - options.Kerf/2))
+	}
+	return front, back
+}
+
 func (p Plane) ClipMesh(m *fauxgl.Mesh) *fauxgl.Mesh {
-	var triangles []*fauxgl.Triangle
-	for _, t := range m.Triangles {
-		if t.IsDegenerate() {
-			continue
+	return p.cut(m, Options{}).clipMesh(m)
+}
+
+func (p cutPlane) clipMesh(m *fauxgl.Mesh) *fauxgl.Mesh {
+	wn := workers(len(m.Triangles))
+	results := make([][]*fauxgl.Triangle, wn)
+	parallel(len(m.Triangles), wn, func(wi, i0, i1 int) {
+		var triangles []*fauxgl.Triangle
+		for _, t := range m.Triangles[i0:i1] {
+			if t.IsDegenerate() {
+				continue
+			}
+			f1 := p.pointInFront(t.V1.Position)
+			f2 := p.pointInFront(t.V2.Position)
+			f3 := p.pointInFront(t.V3.Position)
+			if f1 && f2 && f3 {
+				triangles = append(triangles, t)
+			} else if f1 || f2 || f3 {
+				triangles = append(triangles, p.clipTriangle(t)...)
+			}
 		}
-		f1 := p.pointInFront(t.V1.Position)
-		f2 := p.pointInFront(t.V2.Position)
-		f3 := p.pointInFront(t.V3.Position)
-		if f1 && f2 && f3 {
-			triangles = append(triangles, t)
-		} else if f1 || f2 || f3 {
-			triangles = append(triangles, p.clipTriangle(t)...)
+		results[wi] = triangles
+	})
+	return fauxgl.NewTriangleMesh(mergeTriangles(results))
+}
+
+type planeSplit struct {
+	Front, Back *fauxgl.Mesh
+
+	// cap segments of each part, welded, with the back ones directed for
+	// the cap behind the plane
+	FrontSegments, BackSegments []Path
+
+	// some vertex lies on the plane, so the two caps can differ
+	Touches bool
+}
+
+// classifies each triangle once, cutting the ones that straddle the plane
+// at crossings shared by the front and back pieces and cap segments
+func (p cutPlane) splitMesh(m *fauxgl.Mesh) planeSplit {
+	type result struct {
+		front, back                 []*fauxgl.Triangle
+		frontSegments, backSegments []Path
+		touches                     bool
+	}
+	wn := workers(len(m.Triangles))
+	results := make([]result, wn)
+	parallel(len(m.Triangles), wn, func(wi, i0, i1 int) {
+		r := &results[wi]
+		for _, t := range m.Triangles[i0:i1] {
+			d := [3]int{p.side(t.V1.Position), p.side(t.V2.Position), p.side(t.V3.Position)}
+			if d[0] > 0 && d[1] > 0 && d[2] > 0 {
+				if !t.IsDegenerate() {
+					r.front = append(r.front, t)
+				}
+				continue
+			}
+			if d[0] < 0 && d[1] < 0 && d[2] < 0 {
+				if !t.IsDegenerate() {
+					r.back = append(r.back, t)
+				}
+				continue
+			}
+			r.touches = r.touches || d[0] == 0 || d[1] == 0 || d[2] == 0
+			front, back, fs, bs := p.cutTriangle(t, d)
+			r.front = append(r.front, front...)
+			r.back = append(r.back, back...)
+			if fs != nil {
+				r.frontSegments = append(r.frontSegments, fs)
+			}
+			if bs != nil {
+				r.backSegments = append(r.backSegments, bs)
+			}
 		}
+	})
+	var s planeSplit
+	fronts := make([][]*fauxgl.Triangle, wn)
+	backs := make([][]*fauxgl.Triangle, wn)
+	frontSegments := make([][]Path, wn)
+	backSegments := make([][]Path, wn)
+	for i, r := range results {
+		fronts[i], backs[i] = r.front, r.back
+		frontSegments[i], backSegments[i] = r.frontSegments, r.backSegments
+		s.Touches = s.Touches || r.touches
 	}
-	return fauxgl.NewTriangleMesh(triangles)
+	s.FrontSegments = weldSegments(frontSegments, p.tolerance)
+	s.BackSegments = weldSegments(backSegments, p.tolerance)
+	// the back cap is the front cap unless a vertex lies on the plane
+	behind := s.FrontSegments
+	if s.Touches {
+		behind = s.BackSegments
+	}
+	s.Front = p.weldWalls(fauxgl.NewTriangleMesh(mergeTriangles(fronts)), s.FrontSegments)
+	s.Back = p.weldWalls(fauxgl.NewTriangleMesh(mergeTriangles(backs)), behind)
+	return s
 }
 
-func (p Plane) SliceMesh(m *fauxgl.Mesh) *fauxgl.Mesh {
-	p.Point = p.Point.RoundPlaces(9)
-	var paths []Path
-	for _, t := range m.Triangles {
-		if v1, v2, ok := p.intersectTriangle(t); ok {
-			paths = append(paths, Path{v1, v2})
+// pieces of a triangle with vertices on both sides of or on the plane, and
+// its cap segments, from one set of edge crossings; d holds the sides of
+// its vertices
+func (p cutPlane) cutTriangle(t *fauxgl.Triangle, d [3]int) (front, back []*fauxgl.Triangle, frontSegment, backSegment Path) {
+	v := [3]fauxgl.Vector{t.V1.Position, t.V2.Position, t.V3.Position}
+	var x [3]fauxgl.Vector
+	var inFront, inBack [3]bool
+	for i := range v {
+		inFront[i] = d[i] > 0
+		inBack[i] = d[i] < 0
+	}
+	for i := range v {
+		j := (i + 1) % 3
+		if inFront[i] != inFront[j] || inBack[i] != inBack[j] {
+			x[i] = p.clipSegment(v[i], v[j])
 		}
 	}
-	paths = joinPaths(paths)
-	paths = projectPaths(paths, p)
-	polygons := pathsToPolygons(paths)
 
-	// im := renderPolygons(polygons)
-	// gg.SavePNG("out.png", im)
+	// the polygon on each side walks the triangle, keeping the vertices on
+	// that side or on the plane and the crossings of edges through it
+	var frontPoints, backPoints []fauxgl.Vector
+	for i := range v {
+		if d[i] >= 0 {
+			frontPoints = append(frontPoints, v[i])
+		}
+		if d[i] <= 0 {
+			backPoints = append(backPoints, v[i])
+		}
+		if d[i]*d[(i+1)%3] < 0 {
+			frontPoints = append(frontPoints, x[i])
+			backPoints = append(backPoints, x[i])
+		}
+	}
+	if !t.IsDegenerate() {
+		if d[0] > 0 || d[1] > 0 || d[2] > 0 {
+			front = fanTriangles(t, frontPoints)
+		}
+		if d[0] < 0 || d[1] < 0 || d[2] < 0 {
+			back = fanTriangles(t, backPoints)
+		}
+	}
+
+	if a, b, ok := crossingSegment(inFront, x); ok {
+		frontSegment = Path{a, b}
+	}
+	if a, b, ok := crossingSegment(inBack, x); ok {
+		backSegment = Path{b, a}
+	}
+	return
+}
+
+func (p Plane) SliceMesh(m *fauxgl.Mesh) *fauxgl.Mesh {
+	return p.slice(m, nil)
+}
 
+func (p Plane) SliceMeshReport(m *fauxgl.Mesh) (*fauxgl.Mesh, *Report) {
+	report := &Report{}
+	mesh := p.slice(m, report)
+	return mesh, report
+}
+
+func (p Plane) slice(m *fauxgl.Mesh, report *Report) *fauxgl.Mesh {
+	c := p.cut(m, Options{})
+	report.countDegenerate(m)
+	polygons, points := c.capPolygons(c.intersectMesh(m), report)
+	return c.capMesh(polygons, points, report)
+}
+
+// triangulated cap on the mesh points the polygons were projected from,
+// counting empty caps and the cap area in the report
+func (p cutPlane) capMesh(polygons []Polygon, points map[fauxgl.Vector]fauxgl.Vector, report *Report) *fauxgl.Mesh {
 	mesh := fauxgl.NewEmptyMesh()
 	for _, polygon := range polygons {
-		mesh.Add(polygon.Triangulate(p))
+		triangles := polygon.triangulateCap(p, points)
+		if report != nil && len(triangles.Triangles) == 0 {
+			report.EmptyCaps++
+		}
+		mesh.Add(triangles)
+	}
+	if report != nil {
+		report.CapArea = mesh.SurfaceArea()
 	}
 	return mesh
 }
 
-func (p Plane) clipTriangle(t *fauxgl.Triangle) []*fauxgl.Triangle {
+func (p Plane) slicePolygons(m *fauxgl.Mesh, report *Report) []Polygon {
+	c := p.cut(m, Options{})
+	report.countDegenerate(m)
+	polygons, _ := c.capPolygons(c.intersectMesh(m), report)
+	return polygons
+}
+
+// joins welded cap segments into the polygons of a cap, with the mesh
+// points their path points were projected from
+func (p Plane) capPolygons(segments []Path, report *Report) ([]Polygon, map[fauxgl.Vector]fauxgl.Vector) {
+	paths, open, dropped := joinPaths(segments)
+	polygons, paths, points := p.polygonize(paths)
+
+	// im := renderPolygons(polygons)
+	// gg.SavePNG("out.png", im)
+
+	if report != nil {
+		report.ClosedLoops = len(paths)
+		report.OpenChains = open
+		report.DroppedLoops = dropped
+		report.NestingDepth = nestingDepth(paths)
+	}
+	return polygons, points
+}
+
+func (p cutPlane) behindSegments(m *fauxgl.Mesh) []Path {
+	paths := p.Negate().intersectMesh(m)
+	for _, path := range paths {
+		path[0], path[1] = path[1], path[0]
+	}
+	return paths
+}
+
+// projects closed paths into polygons and maps the projected points back
+// to the points they were projected from, so caps share their vertices
+// with the clipped walls
+func (p Plane) polygonize(paths []Path) ([]Polygon, []Path, map[fauxgl.Vector]fauxgl.Vector) {
+	projected := projectPaths(paths, p)
+	points := make(map[fauxgl.Vector]fauxgl.Vector)
+	for i, path := range paths {
+		for j, v := range path {
+			points[projected[i][j]] = v
+		}
+	}
+	return pathsToPolygons(projected), projected, points
+}
+
+// segments where the triangles cross the plane, with ends closer than the
+// tolerance snapped together, in triangle order
+func (p cutPlane) intersectMesh(m *fauxgl.Mesh) []Path {
+	wn := workers(len(m.Triangles))
+	results := make([][]Path, wn)
+	parallel(len(m.Triangles), wn, func(wi, i0, i1 int) {
+		var paths []Path
+		for _, t := range m.Triangles[i0:i1] {
+			if v1, v2, ok := p.intersectTriangle(t); ok {
+				paths = append(paths, Path{v1, v2})
+			}
+		}
+		results[wi] = paths
+	})
+	return weldSegments(results, p.tolerance)
+}
+
+// segments with ends closer than the tolerance snapped together, in order
+func weldSegments(results [][]Path, tolerance float64) []Path {
+	var paths []Path
+	w := newWelder(tolerance)
+	for _, result := range results {
+		for _, path := range result {
+			path[0] = w.snap(path[0])
+			path[1] = w.snap(path[1])
+			// segments of slivers can collapse, and would hide the segment
+			// leaving the same point from joinPaths
+			if path[0] == path[1] {
+				continue
+			}
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// moves wall vertices within the tolerance of the plane onto the nearest
+// cap point, where welding the segments merged crossings into it, and drops
+// the triangles that collapse, so the walls meet the cap edge to edge
+func (p cutPlane) weldWalls(walls *fauxgl.Mesh, segments []Path) *fauxgl.Mesh {
+	w := newWelder(p.tolerance)
+	for _, s := range segments {
+		w.add(s[0])
+		w.add(s[1])
+	}
+	n := p.Normal.Normalize()
+	snap := func(v fauxgl.Vector) fauxgl.Vector {
+		if math.Abs(v.Sub(p.Point).Dot(n)) > p.tolerance {
+			return v
+		}
+		q, _ := w.nearest(v)
+		return q
+	}
+	var triangles []*fauxgl.Triangle
+	for _, t := range walls.Triangles {
+		p1, p2, p3 := snap(t.V1.Position), snap(t.V2.Position), snap(t.V3.Position)
+		if p1 == t.V1.Position && p2 == t.V2.Position && p3 == t.V3.Position {
+			triangles = append(triangles, t)
+			continue
+		}
+		if p1 == p2 || p2 == p3 || p3 == p1 {
+			continue
+		}
+		u := *t
+		u.V1.Position, u.V2.Position, u.V3.Position = p1, p2, p3
+		triangles = append(triangles, &u)
+	}
+	return fauxgl.NewTriangleMesh(triangles)
+}
+
+// whether the mesh has vertices on both sides
+func (p Plane) cuts(m *fauxgl.Mesh) bool {
+	var front, back bool
+	for _, t := range m.Triangles {
+		for _, v := range [3]fauxgl.Vector{t.V1.Position, t.V2.Position, t.V3.Position} {
+			switch p.side(v) {
+			case 1:
+				front = true
+			case -1:
+				back = true
+			}
+			if front && back {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (p Plane) touches(m *fauxgl.Mesh) bool {
+	for _, t := range m.Triangles {
+		if p.side(t.V1.Position) == 0 || p.side(t.V2.Position) == 0 || p.side(t.V3.Position) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (p Plane) capPoint(point fauxgl.Vector) fauxgl.Vector {
+	return p.Unproject(point)
+}
+
+func (p cutPlane) clipTriangle(t *fauxgl.Triangle) []*fauxgl.Triangle {
 	p1 := t.V1.Position
 	p2 := t.V2.Position
 	p3 := t.V3.Position
 	points := []fauxgl.Vector{p1, p2, p3}
-	newPoints := sutherlandHodgman(points, []Plane{p})
+	return fanTriangles(t, sutherlandHodgman(points, []cutPlane{p}))
+}
+
+// triangles fanning out over the clipped polygon of t, with the vertex
+// attributes interpolated at its points
+func fanTriangles(t *fauxgl.Triangle, newPoints []fauxgl.Vector) []*fauxgl.Triangle {
+	p1 := t.V1.Position
+	p2 := t.V2.Position
+	p3 := t.V3.Position
+
+	// snapped intersections can repeat a corner
+	var unique []fauxgl.Vector
+	for i, v := range newPoints {
+		if v != newPoints[(i+1)%len(newPoints)] {
+			unique = append(unique, v)
+		}
+	}
+	newPoints = unique
+
 	var result []*fauxgl.Triangle
 	for i := 2; i < len(newPoints); i++ {
 		b1 := fauxgl.Barycentric(p1, p2, p3, newPoints[0])
@@ -81,61 +599,89 @@ func (p Plane) clipTriangle(t *fauxgl.Triangle) []*fauxgl.Triangle {
 		v1 := fauxgl.InterpolateVertexes(t.V1, t.V2, t.V3, b1)
 		v2 := fauxgl.InterpolateVertexes(t.V1, t.V2, t.V3, b2)
 		v3 := fauxgl.InterpolateVertexes(t.V1, t.V2, t.V3, b3)
+		// keep the exact clipped positions so the walls meet the cap
+		v1.Position = newPoints[0]
+		v2.Position = newPoints[i-1]
+		v3.Position = newPoints[i]
 		result = append(result, fauxgl.NewTriangle(v1, v2, v3))
 	}
 	return result
 }
 
+// points on the plane are behind it
 func (p Plane) pointInFront(v fauxgl.Vector) bool {
-	return v.Sub(p.Point).Dot(p.Normal) > 0
+	return p.side(v) > 0
+}
+
+// point where a segment known to cross the plane meets it; both
+// directions of an edge give the same point, and points within the
+// tolerance of an end snap to it
+func (p cutPlane) clipSegment(v0, v1 fauxgl.Vector) fauxgl.Vector {
+	if vectorLess(v1, v0) {
+		v0, v1 = v1, v0
+	}
+	n := p.Normal.Normalize()
+	d0 := v0.Sub(p.Point).Dot(n)
+	d1 := v1.Sub(p.Point).Dot(n)
+	t := math.Max(0, math.Min(1, d0/(d0-d1)))
+	if p.side(v0) == 0 || t*v0.Distance(v1) <= p.tolerance {
+		return v0
+	}
+	if p.side(v1) == 0 || (1-t)*v0.Distance(v1) <= p.tolerance {
+		return v1
+	}
+	return v0.Add(v1.Sub(v0).MulScalar(t))
 }
 
-func (p Plane) intersectSegment(v0, v1 fauxgl.Vector) (fauxgl.Vector, bool) {
-	// TODO: do slicing in Z, rotate mesh to plane
-	v0 = v0.RoundPlaces(9).Add(p.Normal.MulScalar(5e-10))
-	v1 = v1.RoundPlaces(9).Add(p.Normal.MulScalar(5e-10))
-	u := v1.Sub(v0)
-	w := v0.Sub(p.Point)
-	d := p.Normal.Dot(u)
-	if d > -1e-9 && d < 1e-9 {
-		return fauxgl.Vector{}, false
+// segment where the triangle crosses the plane, directed so that loops
+// run counterclockwise around the part in front seen from behind
+func (p cutPlane) intersectTriangle(t *fauxgl.Triangle) (fauxgl.Vector, fauxgl.Vector, bool) {
+	v := [3]fauxgl.Vector{t.V1.Position, t.V2.Position, t.V3.Position}
+	var inside [3]bool
+	var x [3]fauxgl.Vector
+	for i := range v {
+		inside[i] = p.pointInFront(v[i])
 	}
-	n := -p.Normal.Dot(w)
-	t := n / d
-	if t < 0 || t > 1 {
-		return fauxgl.Vector{}, false
+	for i := range v {
+		if j := (i + 1) % 3; inside[i] != inside[j] {
+			x[i] = p.clipSegment(v[i], v[j])
+		}
 	}
-	return v0.Add(u.MulScalar(t)), true
+	return crossingSegment(inside, x)
 }
 
-func (p Plane) intersectTriangle(t *fauxgl.Triangle) (fauxgl.Vector, fauxgl.Vector, bool) {
-	v1, ok1 := p.intersectSegment(t.V1.Position, t.V2.Position)
-	v2, ok2 := p.intersectSegment(t.V2.Position, t.V3.Position)
-	v3, ok3 := p.intersectSegment(t.V3.Position, t.V1.Position)
+// segment between the crossings x of the edges whose ends differ in
+// inside, with x[i] on the edge from vertex i, directed so that loops run
+// counterclockwise around the inside seen from the outside; the direction
+// follows from the winding and the side of the vertex alone on its side,
+// not from the normal, so slivers are oriented like their neighbors
+func crossingSegment(inside [3]bool, x [3]fauxgl.Vector) (fauxgl.Vector, fauxgl.Vector, bool) {
+	ok1 := inside[0] != inside[1]
+	ok2 := inside[1] != inside[2]
+	ok3 := inside[2] != inside[0]
+	// p1 is on the edge into the lone vertex and p2 on the edge out of it
 	var p1, p2 fauxgl.Vector
+	var lone int
 	if ok1 && ok2 {
-		p1, p2 = v1, v2
-	} else if ok1 && ok3 {
-		p1, p2 = v1, v3
+		p1, p2, lone = x[0], x[1], 1
 	} else if ok2 && ok3 {
-		p1, p2 = v2, v3
+		p1, p2, lone = x[1], x[2], 2
+	} else if ok1 && ok3 {
+		p1, p2, lone = x[2], x[0], 0
 	} else {
 		return fauxgl.Vector{}, fauxgl.Vector{}, false
 	}
-	p1 = p1.RoundPlaces(8)
-	p2 = p2.RoundPlaces(8)
 	if p1 == p2 {
 		return fauxgl.Vector{}, fauxgl.Vector{}, false
 	}
-	n := p2.Sub(p1).Cross(p.Normal)
-	if n.Dot(t.Normal()) < 0 {
+	if inside[lone] {
 		return p1, p2, true
 	} else {
 		return p2, p1, true
 	}
 }
 
-func sutherlandHodgman(points []fauxgl.Vector, planes []Plane) []fauxgl.Vector {
+func sutherlandHodgman(points []fauxgl.Vector, planes []cutPlane) []fauxgl.Vector {
 	output := points
 	for _, plane := range planes {
 		input := output
@@ -147,16 +693,26 @@ func sutherlandHodgman(points []fauxgl.Vector, planes []Plane) []fauxgl.Vector {
 		for _, e := range input {
 			if plane.pointInFront(e) {
 				if !plane.pointInFront(s) {
-					x, _ := plane.intersectSegment(s, e)
-					output = append(output, x)
+					output = append(output, plane.clipSegment(s, e))
 				}
 				output = append(output, e)
 			} else if plane.pointInFront(s) {
-				x, _ := plane.intersectSegment(s, e)
-				output = append(output, x)
+				output = append(output, plane.clipSegment(s, e))
 			}
 			s = e
 		}
 	}
 	return output
 }
+
+func mergeTriangles(results [][]*fauxgl.Triangle) []*fauxgl.Triangle {
+	n := 0
+	for _, result := range results {
+		n += len(result)
+	}
+	triangles := make([]*fauxgl.Triangle, 0, n)
+	for _, result := range results {
+		triangles = append(triangles, result...)
+	}
+	return triangles
+}
@@ -0,0 +1,180 @@
+package choppy
+
+import (
+	"math"
+	"testing"
+
+	"github.com/fogleman/fauxgl"
+)
+
+type chopCase struct {
+	name        string
+	mesh        *fauxgl.Mesh
+	plane       Plane
+	front, back float64
+}
+
+func chopCases() []chopCase {
+	cube := testBox(fauxgl.Vector{}, fauxgl.Vector{20, 20, 20})
+	sphere := testSphere(fauxgl.Vector{}, 10, 16)
+	cylinder := testCylinder(10, 0, 20, 24)
+	s := sphere.Volume()
+	c := cylinder.Volume()
+	diagonal := fauxgl.Vector{1, 1, 0}.Normalize()
+	return []chopCase{
+		{"cube at 7.3", cube, MakePlane(fauxgl.Vector{0, 0, 7.3}, fauxgl.Vector{0, 0, 1}), 8000 - 2920, 2920},
+		{"cube through center", cube, MakePlane(fauxgl.Vector{10, 10, 10}, fauxgl.Vector{1, 0, 0}), 4000, 4000},
+		// through the vertical edges at two corners and the face diagonals
+		{"cube through corners", cube, MakePlane(fauxgl.Vector{10, 10, 10}, diagonal), 4000, 4000},
+		{"tilted cube", cube, MakePlane(fauxgl.Vector{10, 10, 10}, fauxgl.Vector{1, 2, 3}), 4000, 4000},
+		{"sphere through poles", sphere, MakePlane(fauxgl.Vector{}, fauxgl.Vector{1, 0, 0}), s / 2, s / 2},
+		{"sphere at equator", sphere, MakePlane(fauxgl.Vector{}, fauxgl.Vector{0, 0, 1}), s / 2, s / 2},
+		{"cylinder through fan centers", cylinder, MakePlane(fauxgl.Vector{}, fauxgl.Vector{0, 1, 0}), c / 2, c / 2},
+	}
+}
+
+func TestChop(t *testing.T) {
+	for _, c := range chopCases() {
+		checkClosed(t, c.name, c.plane.Chop(c.mesh), c.front)
+		checkClosed(t, c.name+" negated", c.plane.Negate().Chop(c.mesh), c.back)
+	}
+}
+
+func TestChopBoth(t *testing.T) {
+	for _, c := range chopCases() {
+		front, back := c.plane.ChopBoth(c.mesh)
+		checkClosed(t, c.name+" front", front, c.front)
+		checkClosed(t, c.name+" back", back, c.back)
+	}
+}
+
+func TestChopWith(t *testing.T) {
+	cube := testBox(fauxgl.Vector{}, fauxgl.Vector{20, 20, 20})
+	plane := MakePlane(fauxgl.Vector{10, 10, 10}, fauxgl.Vector{0, 0, 1})
+	cases := []struct {
+		name        string
+		options     Options
+		front, back float64
+	}{
+		{"plain", Options{}, 4000, 4000},
+		{"kerf", Options{Kerf: 2}, 3600, 3600},
+		{"offset kerf", Options{Kerf: 2, KerfOffset: 3}, 2400, 4800},
+	}
+	for _, c := range cases {
+		front, back, err := plane.ChopWith(cube, c.options)
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+		checkClosed(t, c.name+" front", front, c.front)
+		checkClosed(t, c.name+" back", back, c.back)
+	}
+	if _, _, err := plane.ChopWith(cube, Options{Kerf: -1}); err == nil {
+		t.Error("negative kerf: no error")
+	}
+}
+
+func TestChopMany(t *testing.T) {
+	cube := testBox(fauxgl.Vector{}, fauxgl.Vector{20, 20, 20})
+	planes := []Plane{
+		MakePlane(fauxgl.Vector{10, 0, 0}, fauxgl.Vector{1, 0, 0}),
+		MakePlane(fauxgl.Vector{0, 5, 0}, fauxgl.Vector{0, 1, 0}),
+		MakePlane(fauxgl.Vector{0, 0, 15}, fauxgl.Vector{0, 0, 1}),
+	}
+	parts := ChopMany(cube, planes)
+	if len(parts) != 8 {
+		t.Fatalf("%d parts, want 8", len(parts))
+	}
+	var total float64
+	for _, part := range parts {
+		if n := openEdges(part.Mesh.Triangles); n != 0 {
+			t.Errorf("part %v: %d open edges", part.Mesh.BoundingBox(), n)
+		}
+		box := part.Mesh.BoundingBox()
+		size := box.Size()
+		if v := part.Mesh.Volume(); math.Abs(v-size.X*size.Y*size.Z) > 1e-6 {
+			t.Errorf("part %v: volume %g", box, v)
+		}
+		total += part.Mesh.Volume()
+	}
+	if math.Abs(total-8000) > 1e-6 {
+		t.Errorf("total volume %g, want 8000", total)
+	}
+}
+
+func TestChopManyWith(t *testing.T) {
+	cube := testBox(fauxgl.Vector{}, fauxgl.Vector{20, 20, 20})
+	planes := []Plane{
+		MakePlane(fauxgl.Vector{10, 0, 0}, fauxgl.Vector{1, 0, 0}),
+		MakePlane(fauxgl.Vector{0, 0, 10}, fauxgl.Vector{0, 0, 1}),
+	}
+	parts, err := ChopManyWith(cube, planes, Options{Kerf: 2, Tolerance: 1e-3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parts) != 4 {
+		t.Fatalf("%d parts, want 4", len(parts))
+	}
+	for i, part := range parts {
+		checkClosed(t, "kerf part", part.Mesh, 9*20*9)
+		if len(part.Planes) != 2 {
+			t.Errorf("part %d: %d planes", i, len(part.Planes))
+		}
+		// the planes are the faces of the kerf, facing into the part
+		for _, plane := range part.Planes {
+			n := plane.Normal.Normalize()
+			d := math.Inf(1)
+			for _, u := range part.Mesh.Triangles {
+				for _, v := range triangleCorners(u) {
+					d = math.Min(d, v.Sub(plane.Point).Dot(n))
+				}
+			}
+			if math.Abs(d) > 1e-9 {
+				t.Errorf("part %d: nearest point %g in front of %v", i, d, plane)
+			}
+		}
+	}
+	if _, err := ChopManyWith(cube, planes, Options{Kerf: -1}); err == nil {
+		t.Error("negative kerf: no error")
+	}
+}
+
+func TestTolerance(t *testing.T) {
+	cases := []struct {
+		name    string
+		scale   float64
+		options Options
+	}{
+		// micrometers in meters
+		{"micrometers", 1e-6, Options{}},
+		{"micrometers with a tolerance", 1e-6, Options{Tolerance: 1e-9}},
+		// meters in millimeters
+		{"meters", 1e3, Options{}},
+	}
+	for _, c := range cases {
+		s := c.scale
+		cube := testBox(fauxgl.Vector{}, fauxgl.Vector{20 * s, 20 * s, 20 * s})
+		plane := MakePlane(fauxgl.Vector{0, 0, 7.3 * s}, fauxgl.Vector{0, 0, 1})
+		front, back, err := plane.ChopWith(cube, c.options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkClosed(t, c.name+" front", front, 20*20*12.7*s*s*s)
+		checkClosed(t, c.name+" back", back, 20*20*7.3*s*s*s)
+
+		sphere := testSphere(fauxgl.Vector{}, 10*s, 16)
+		front, back, err = MakePlane(fauxgl.Vector{}, fauxgl.Vector{1, 1, 1}).ChopWith(sphere, c.options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkClosed(t, c.name+" sphere front", front, sphere.Volume()/2)
+		checkClosed(t, c.name+" sphere back", back, sphere.Volume()/2)
+
+		box := fauxgl.Box{fauxgl.Vector{5 * s, 5 * s, 5 * s}, fauxgl.Vector{15 * s, 15 * s, 25 * s}}
+		clipped, err := ClipConvexWith(cube, BoxPlanes(box), c.options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkClosed(t, c.name+" clipped", clipped, 10*10*15*s*s*s)
+	}
+}
@@ -2,10 +2,10 @@ package choppy
 
 import (
 	"image"
+	"math"
 
 	"github.com/fogleman/fauxgl"
 	"github.com/fogleman/gg"
-	"github.com/fogleman/triangle"
 )
 
 type Polygon struct {
@@ -53,104 +53,158 @@ func renderPolygons(polygons []Polygon) image.Image {
 	return dc.Image()
 }
 
+// cap facing away from the plane's normal, with flat normals and texture
+// coordinates from the plane's U and V axes
 func (polygon Polygon) Triangulate(plane Plane) *fauxgl.Mesh {
-	paths := make([]Path, len(polygon.Interiors)+1)
-	paths[0] = polygon.Exterior
-	copy(paths[1:], polygon.Interiors)
+	return polygon.triangulateCap(cutPlane{Plane: plane}, nil)
+}
 
-	var points [][2]float64
-	var segments [][2]int32
-	for _, path := range paths {
-		// path = path[1:]
-		start := len(points)
-		for i, p := range path {
-			points = append(points, [2]float64{p.X, p.Y})
-			i1 := len(points) - 1
-			i2 := i1 + 1
-			if i == len(path)-1 {
-				i2 = start
-			}
-			segments = append(segments, [2]int32{int32(i1), int32(i2)})
+// Triangulate in the cap color of the cut, placing each point on the mesh
+// point it was projected from where points has one
+func (polygon Polygon) triangulateCap(plane cutPlane, points map[fauxgl.Vector]fauxgl.Vector) *fauxgl.Mesh {
+	uv, indexes := triangulate(polygon.paths())
+	normal := plane.Normal.Normalize().Negate()
+	vertex := func(point [2]float64) fauxgl.Vertex {
+		p := fauxgl.Vector{point[0], point[1], 0}
+		var v fauxgl.Vertex
+		v.Position = plane.capPoint(p)
+		if q, ok := points[p]; ok {
+			v.Position = q
 		}
+		v.Normal = normal
+		v.Texture = p
+		v.Color = plane.capColor
+		return v
+	}
+	var triangles []*fauxgl.Triangle
+	for _, t := range indexes {
+		v1 := vertex(uv[t[0]])
+		v2 := vertex(uv[t[1]])
+		v3 := vertex(uv[t[2]])
+		triangles = append(triangles, fauxgl.NewTriangle(v1, v2, v3))
 	}
+	return fauxgl.NewTriangleMesh(triangles)
+}
 
-	var holes [][2]float64
-	for _, hole := range polygon.Interiors {
-		p, _ := hole.HolePoint()
-		holes = append(holes, [2]float64{p.X, p.Y})
+func (polygon Polygon) Area() float64 {
+	result := polygon.Exterior.Area()
+	for _, path := range polygon.Interiors {
+		result -= path.Area()
 	}
+	return result
+}
 
-	if len(segments) < 3 {
-		return fauxgl.NewEmptyMesh()
+func (polygon Polygon) Perimeter() float64 {
+	result := polygon.Exterior.Perimeter()
+	for _, path := range polygon.Interiors {
+		result += path.Perimeter()
 	}
+	return result
+}
 
-	in := triangle.NewTriangulateIO()
-	in.SetPoints(points)
-	in.SetSegments(segments)
-	if len(holes) > 0 {
-		in.SetHoles(holes)
+func (polygon Polygon) ContainsPoint(p fauxgl.Vector) bool {
+	if !polygon.Exterior.ContainsPoint(p) {
+		return false
 	}
-	opts := triangle.NewOptions()
-	opts.ConformingDelaunay = false
-	opts.SegmentSplitting = triangle.NoSplitting
-	out := triangle.Triangulate(in, opts, false)
-	points = out.Points()
-	var triangles []*fauxgl.Triangle
-	for _, t := range out.Triangles() {
-		point1 := points[t[0]]
-		point2 := points[t[1]]
-		point3 := points[t[2]]
-		p1 := plane.Unproject(fauxgl.Vector{point1[0], point1[1], 0}).RoundPlaces(8)
-		p2 := plane.Unproject(fauxgl.Vector{point2[0], point2[1], 0}).RoundPlaces(8)
-		p3 := plane.Unproject(fauxgl.Vector{point3[0], point3[1], 0}).RoundPlaces(8)
-		ft := fauxgl.NewTriangleForPoints(p1, p2, p3)
-		triangles = append(triangles, ft)
+	for _, path := range polygon.Interiors {
+		if path.ContainsPoint(p) {
+			return false
+		}
 	}
-	triangle.FreeTriangulateIO(in)
-	triangle.FreeTriangulateIO(out)
-	return fauxgl.NewTriangleMesh(triangles)
+	return true
+}
+
+// distance from p to the nearest edge of the exterior or interiors
+func (polygon Polygon) BoundaryDistance(p fauxgl.Vector) float64 {
+	result := polygon.Exterior.BoundaryDistance(p)
+	for _, path := range polygon.Interiors {
+		result = math.Min(result, path.BoundaryDistance(p))
+	}
+	return result
+}
+
+// smallest distance across the solid, measured from the middle of each edge
+// straight into the polygon to the boundary on the other side, so a thin
+// strip attached to a thick part counts as thin
+func (polygon Polygon) Thickness() float64 {
+	paths := polygon.paths()
+	result := math.Inf(1)
+	for k, path := range paths {
+		// the solid is left of a counterclockwise exterior and right of a
+		// counterclockwise hole
+		sign := 1.0
+		if path.IsHole() != (k > 0) {
+			sign = -1
+		}
+		for i, a := range path {
+			b := path[(i+1)%len(path)]
+			if a == b {
+				continue
+			}
+			m := a.Add(b).MulScalar(0.5)
+			d := leftNormal(b.Sub(a)).MulScalar(sign)
+			for _, other := range paths {
+				for j, c := range other {
+					e := other[(j+1)%len(other)]
+					// only edges nearer than the thinnest found so far matter
+					if math.Min(c.X, e.X) > m.X+result || math.Max(c.X, e.X) < m.X-result ||
+						math.Min(c.Y, e.Y) > m.Y+result || math.Max(c.Y, e.Y) < m.Y-result {
+						continue
+					}
+					if t, ok := raySegment(m, d, c, e); ok {
+						result = math.Min(result, t)
+					}
+				}
+			}
+		}
+	}
+	return result
+}
+
+// distance along the unit direction d from o to the segment from a to b
+func raySegment(o, d, a, b fauxgl.Vector) (float64, bool) {
+	ab := b.Sub(a)
+	cross := d.X*ab.Y - d.Y*ab.X
+	if cross == 0 {
+		return 0, false
+	}
+	ao := a.Sub(o)
+	t := (ao.X*ab.Y - ao.Y*ab.X) / cross
+	s := (ao.X*d.Y - ao.Y*d.X) / cross
+	if t <= 0 || s < 0 || s > 1 {
+		return 0, false
+	}
+	return t, true
 }
 
 func pathsToPolygons(paths []Path) []Polygon {
+	tree := newPathTree(paths)
+	children := make([][]int, len(paths))
+	for i, parent := range tree.parents {
+		if parent >= 0 {
+			children[parent] = append(children[parent], i)
+		}
+	}
 	var result []Polygon
 	seen := make([]bool, len(paths))
 	done := false
 	for !done {
 		done = true
 		for i, p := range paths {
-			if seen[i] {
-				continue
-			}
-			// see if p is a top-level contour (no others contain it)
-			ok := true
-			for j, q := range paths {
-				if i != j && !seen[j] && q.ContainsPath(p) {
-					ok = false
-					break
-				}
-			}
-			if !ok {
+			// p is a top-level contour once the paths containing it are used
+			parent := tree.parents[i]
+			if seen[i] || parent >= 0 && !seen[parent] {
 				continue
 			}
 			seen[i] = true
-			// see which holes q are only contained by p (not contained by any other r)
+			// holes are the children of p winding clockwise; children winding the
+			// other way are islands and become polygons of their own
 			var holes []Path
-			for j, q := range paths {
-				if seen[j] || !p.ContainsPath(q) || !q.IsHole() {
-					continue
-				}
-				ok := true
-				for k, r := range paths {
-					if i != k && j != k && !seen[k] && r.ContainsPath(q) {
-						ok = false
-						break
-					}
-				}
-				if !ok {
-					continue
+			for _, j := range children[i] {
+				if paths[j].IsHole() {
+					seen[j] = true
+					holes = append(holes, paths[j])
 				}
-				seen[j] = true
-				holes = append(holes, q)
 			}
 			// create polygon
 			result = append(result, Polygon{p, holes})
@@ -0,0 +1,67 @@
+package choppy
+
+import (
+	"math"
+	"testing"
+
+	"github.com/fogleman/fauxgl"
+)
+
+func TestPolygonThickness(t *testing.T) {
+	rect := func(x0, y0, x1, y1 float64) Path {
+		return Path{{x0, y0, 0}, {x1, y0, 0}, {x1, y1, 0}, {x0, y1, 0}}
+	}
+	cases := []struct {
+		name    string
+		polygon Polygon
+		want    float64
+	}{
+		{"square", Polygon{rect(0, 0, 10, 10), nil}, 10},
+		{"bar", Polygon{rect(0, 0, 10, 2), nil}, 2},
+		{"clockwise bar", Polygon{reversePath(rect(0, 0, 10, 2)), nil}, 2},
+		// a square with a thin strip along the top of its right side
+		{"square with strip", Polygon{Path{
+			{0, 0, 0}, {10, 0, 0}, {10, 9.5, 0}, {30, 9.5, 0},
+			{30, 10, 0}, {0, 10, 0}}, nil}, 0.5},
+		{"frame", Polygon{rect(0, 0, 10, 10), []Path{reversePath(rect(1, 3, 9, 7))}}, 1},
+	}
+	for _, c := range cases {
+		if got := c.polygon.Thickness(); math.Abs(got-c.want) > 1e-9 {
+			t.Errorf("%s: thickness %g, want %g", c.name, got, c.want)
+		}
+	}
+}
+
+func TestThicknessCost(t *testing.T) {
+	// a plate with a thin fin sticking out of its top
+	plate := testBox(fauxgl.Vector{0, 0, 0}, fauxgl.Vector{20, 20, 10})
+	fin := testBox(fauxgl.Vector{0, 9, 10}, fauxgl.Vector{20, 11, 30})
+	mesh := fauxgl.NewTriangleMesh(append(plate.Triangles, fin.Triangles...))
+	high := MakePlane(fauxgl.Vector{0, 0, 20}, fauxgl.Vector{0, 0, 1})
+	low := MakePlane(fauxgl.Vector{0, 0, 5}, fauxgl.Vector{0, 0, 1})
+	if c := high.cost(mesh, ThicknessCost); math.Abs(c+2) > 1e-9 {
+		t.Errorf("cost across the fin %g, want -2", c)
+	}
+	if c := low.cost(mesh, ThicknessCost); math.Abs(c+20) > 1e-9 {
+		t.Errorf("cost across the plate %g, want -20", c)
+	}
+}
+
+func TestPathTree(t *testing.T) {
+	paths := []Path{
+		square(1, 1, 3, 3),
+		// a hole sharing the corner of the path around it
+		reversePath(square(0, 0, 4, 4)),
+		square(0, 0, 10, 10),
+		square(20, 0, 30, 10),
+	}
+	tree := newPathTree(paths)
+	parents := []int{1, 2, -1, -1}
+	depths := []int{2, 1, 0, 0}
+	for i := range paths {
+		if tree.parents[i] != parents[i] || tree.depths[i] != depths[i] {
+			t.Errorf("path %d: parent %d at depth %d, want %d at %d",
+				i, tree.parents[i], tree.depths[i], parents[i], depths[i])
+		}
+	}
+}
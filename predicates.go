@@ -0,0 +1,132 @@
+package choppy
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/fogleman/fauxgl"
+)
+
+// half a unit in the last place of 1, and the error bound of the float
+// orientation test from Shewchuk's adaptive predicates
+const (
+	epsilon       = 1.0 / (1 << 53)
+	ccwErrorBound = (3 + 16*epsilon) * epsilon
+	o3dErrorBound = (7 + 56*epsilon) * epsilon
+)
+
+// positive if a, b, c wind counterclockwise, negative if clockwise and zero
+// if collinear; the sign is always exact, falling back to rational
+// arithmetic when the float result is too close to call
+func orient2d(ax, ay, bx, by, cx, cy float64) float64 {
+	l := (ax - cx) * (by - cy)
+	r := (ay - cy) * (bx - cx)
+	det := l - r
+	var sum float64
+	switch {
+	case l > 0:
+		if r <= 0 {
+			return det
+		}
+		sum = l + r
+	case l < 0:
+		if r >= 0 {
+			return det
+		}
+		sum = -l - r
+	default:
+		return det
+	}
+	if math.Abs(det) >= ccwErrorBound*sum {
+		return det
+	}
+	return float64(orient2dExact(ax, ay, bx, by, cx, cy))
+}
+
+func orient2dExact(ax, ay, bx, by, cx, cy float64) int {
+	acx := ratSub(ax, cx)
+	bcy := ratSub(by, cy)
+	acy := ratSub(ay, cy)
+	bcx := ratSub(bx, cx)
+	l := new(big.Rat).Mul(acx, bcy)
+	r := new(big.Rat).Mul(acy, bcx)
+	return l.Cmp(r)
+}
+
+// 1 if d is on the side of triangle a, b, c its normal points to, -1 if on
+// the other side and 0 if the four points are coplanar, always exactly
+func orient3d(a, b, c, d fauxgl.Vector) int {
+	adx, ady, adz := a.X-d.X, a.Y-d.Y, a.Z-d.Z
+	bdx, bdy, bdz := b.X-d.X, b.Y-d.Y, b.Z-d.Z
+	cdx, cdy, cdz := c.X-d.X, c.Y-d.Y, c.Z-d.Z
+	bc := bdy*cdz - bdz*cdy
+	ca := cdy*adz - cdz*ady
+	ab := ady*bdz - adz*bdy
+	det := adx*bc + bdx*ca + cdx*ab
+	permanent := (math.Abs(bdy*cdz)+math.Abs(bdz*cdy))*math.Abs(adx) +
+		(math.Abs(cdy*adz)+math.Abs(cdz*ady))*math.Abs(bdx) +
+		(math.Abs(ady*bdz)+math.Abs(adz*bdy))*math.Abs(cdx)
+	// det is (a-d).((b-d)x(c-d)), which is negative when d is in front
+	bound := o3dErrorBound * permanent
+	switch {
+	case det > bound:
+		return -1
+	case det < -bound:
+		return 1
+	}
+	return -orient3dExact(a, b, c, d)
+}
+
+func orient3dExact(a, b, c, d fauxgl.Vector) int {
+	adx, ady, adz := ratSub(a.X, d.X), ratSub(a.Y, d.Y), ratSub(a.Z, d.Z)
+	bdx, bdy, bdz := ratSub(b.X, d.X), ratSub(b.Y, d.Y), ratSub(b.Z, d.Z)
+	cdx, cdy, cdz := ratSub(c.X, d.X), ratSub(c.Y, d.Y), ratSub(c.Z, d.Z)
+	cross := func(p, q, r, s *big.Rat) *big.Rat {
+		l := new(big.Rat).Mul(p, q)
+		return l.Sub(l, new(big.Rat).Mul(r, s))
+	}
+	det := new(big.Rat).Mul(adx, cross(bdy, cdz, bdz, cdy))
+	det.Add(det, new(big.Rat).Mul(bdx, cross(cdy, adz, cdz, ady)))
+	det.Add(det, new(big.Rat).Mul(cdx, cross(ady, bdz, adz, bdy)))
+	return det.Sign()
+}
+
+// exact sign of the distance from v to the plane
+func (p Plane) side(v fauxgl.Vector) int {
+	x := (v.X - p.Point.X) * p.Normal.X
+	y := (v.Y - p.Point.Y) * p.Normal.Y
+	z := (v.Z - p.Point.Z) * p.Normal.Z
+	d := x + y + z
+	bound := (math.Abs(x) + math.Abs(y) + math.Abs(z)) * 8 * epsilon
+	switch {
+	case d > bound:
+		return 1
+	case d < -bound:
+		return -1
+	}
+	result := new(big.Rat)
+	result.Add(result, new(big.Rat).Mul(ratSub(v.X, p.Point.X), ratFloat(p.Normal.X)))
+	result.Add(result, new(big.Rat).Mul(ratSub(v.Y, p.Point.Y), ratFloat(p.Normal.Y)))
+	result.Add(result, new(big.Rat).Mul(ratSub(v.Z, p.Point.Z), ratFloat(p.Normal.Z)))
+	return result.Sign()
+}
+
+func ratFloat(x float64) *big.Rat {
+	return new(big.Rat).SetFloat64(x)
+}
+
+func ratSub(a, b float64) *big.Rat {
+	return new(big.Rat).Sub(ratFloat(a), ratFloat(b))
+}
+
+// lexicographic order, used to visit the ends of an edge the same way from
+// both of its triangles
+func vectorLess(a, b fauxgl.Vector) bool {
+	if a.X != b.X {
+		return a.X < b.X
+	}
+	if a.Y != b.Y {
+		return a.Y < b.Y
+	}
+	return a.Z < b.Z
+}
@@ -0,0 +1,86 @@
+package choppy
+
+import (
+	"math"
+	"testing"
+
+	"github.com/fogleman/fauxgl"
+)
+
+func TestOrient2d(t *testing.T) {
+	// points on the line y = x a few units in the last place apart, where
+	// the float determinant is rounding error
+	x := 0.5 + math.Nextafter(1, 2) - 1
+	cases := []struct {
+		name                   string
+		ax, ay, bx, by, cx, cy float64
+		want                   int
+	}{
+		{"counterclockwise", 0, 0, 1, 0, 0, 1, 1},
+		{"clockwise", 0, 0, 0, 1, 1, 0, -1},
+		{"collinear", 0, 0, 1, 1, 0.5, 0.5, 0},
+		{"nearly collinear", 12, 12, 24, 24, x, 0.5, -1},
+		{"nearly collinear other side", 12, 12, 24, 24, 0.5, x, 1},
+	}
+	for _, c := range cases {
+		got := orient2d(c.ax, c.ay, c.bx, c.by, c.cx, c.cy)
+		if sign(got) != c.want {
+			t.Errorf("%s: %g, want sign %d", c.name, got, c.want)
+		}
+	}
+}
+
+func TestOrient3d(t *testing.T) {
+	a := fauxgl.Vector{0, 0, 0}
+	b := fauxgl.Vector{1, 0, 0}
+	c := fauxgl.Vector{0, 1, 0}
+	tiny := math.Nextafter(0, 1)
+	cases := []struct {
+		name string
+		d    fauxgl.Vector
+		want int
+	}{
+		{"in front", fauxgl.Vector{0.3, 0.3, 1}, 1},
+		{"behind", fauxgl.Vector{0.3, 0.3, -1}, -1},
+		{"coplanar", fauxgl.Vector{0.3, 0.3, 0}, 0},
+		{"far coplanar", fauxgl.Vector{1e9, -1e9, 0}, 0},
+		{"barely in front", fauxgl.Vector{1e9, 1e9, tiny}, 1},
+	}
+	for _, e := range cases {
+		if got := orient3d(a, b, c, e.d); got != e.want {
+			t.Errorf("%s: %d, want %d", e.name, got, e.want)
+		}
+	}
+	// the same plane through points far from the origin
+	o := fauxgl.Vector{1e6 + 0.1, 1e6 + 0.2, 1e6 + 0.3}
+	if got := orient3d(o, o.Add(b), o.Add(c), o.Add(fauxgl.Vector{0.5, 0.25, 0})); got != 0 {
+		t.Errorf("offset coplanar: %d", got)
+	}
+}
+
+func TestSide(t *testing.T) {
+	p := MakePlane(fauxgl.Vector{0.1, 0.2, 0.3}, fauxgl.Vector{0, 0, 1})
+	cases := []struct {
+		v    fauxgl.Vector
+		want int
+	}{
+		{fauxgl.Vector{5, 5, 0.3}, 0},
+		{fauxgl.Vector{5, 5, math.Nextafter(0.3, 1)}, 1},
+		{fauxgl.Vector{5, 5, math.Nextafter(0.3, 0)}, -1},
+	}
+	for _, c := range cases {
+		if got := p.side(c.v); got != c.want {
+			t.Errorf("%v: %d, want %d", c.v, got, c.want)
+		}
+	}
+}
+
+func sign(x float64) int {
+	switch {
+	case x > 0:
+		return 1
+	case x < 0:
+		return -1
+	}
+	return 0
+}
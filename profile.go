@@ -0,0 +1,36 @@
+package choppy
+
+import "github.com/fogleman/fauxgl"
+
+type ProfileSample struct {
+	// distance of the slice from the lowest point of the mesh along the normal
+	Offset    float64
+	Area      float64
+	Perimeter float64
+	Loops     int
+	Holes     int
+}
+
+// slices the mesh at n evenly spaced offsets along normal, without building
+// the clipped halves
+func Profile(mesh *fauxgl.Mesh, normal fauxgl.Vector, n int) []ProfileSample {
+	normal = normal.Normalize()
+	lo, hi := extent(mesh, normal)
+	if n <= 0 || hi < lo {
+		return nil
+	}
+	result := make([]ProfileSample, n)
+	for i := range result {
+		offset := (hi - lo) * (float64(i) + 0.5) / float64(n)
+		plane := MakePlane(normal.MulScalar(lo+offset), normal)
+		sample := ProfileSample{Offset: offset}
+		for _, polygon := range plane.slicePolygons(mesh, nil) {
+			sample.Area += polygon.Area()
+			sample.Perimeter += polygon.Perimeter()
+			sample.Loops += 1 + len(polygon.Interiors)
+			sample.Holes += len(polygon.Interiors)
+		}
+		result[i] = sample
+	}
+	return result
+}
@@ -0,0 +1,32 @@
+package choppy
+
+import (
+	"math"
+	"testing"
+
+	"github.com/fogleman/fauxgl"
+)
+
+func TestProfile(t *testing.T) {
+	// a cube with a void in its middle third
+	mesh := testBox(fauxgl.Vector{}, fauxgl.Vector{30, 30, 30})
+	mesh.Add(testVoid(fauxgl.Vector{10, 10, 10}, fauxgl.Vector{20, 20, 20}))
+	want := []ProfileSample{
+		{5, 900, 120, 1, 0},
+		{15, 800, 160, 2, 1},
+		{25, 900, 120, 1, 0},
+	}
+	got := Profile(mesh, fauxgl.Vector{0, 0, 2}, 3)
+	if len(got) != len(want) {
+		t.Fatalf("%d samples, want %d", len(got), len(want))
+	}
+	for i, s := range got {
+		w := want[i]
+		if math.Abs(s.Offset-w.Offset) > 1e-9 || math.Abs(s.Area-w.Area) > 1e-9 || math.Abs(s.Perimeter-w.Perimeter) > 1e-9 || s.Loops != w.Loops || s.Holes != w.Holes {
+			t.Errorf("sample %d: %+v, want %+v", i, s, w)
+		}
+	}
+	if got := Profile(mesh, fauxgl.Vector{0, 0, 1}, 0); got != nil {
+		t.Errorf("no samples: %+v", got)
+	}
+}
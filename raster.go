@@ -0,0 +1,123 @@
+package choppy
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"math"
+
+	"github.com/fogleman/fauxgl"
+	"github.com/fogleman/gg"
+)
+
+type RasterOptions struct {
+	LayerHeight float64
+
+	// size of a pixel in model units and the image resolution; the mesh is
+	// centered on the image
+	PixelSize     float64
+	Width, Height int
+
+	// if false, pixels are thresholded to black or white
+	Antialias bool
+}
+
+type RasterLayer struct {
+	Number   int
+	Z        float64
+	Polygons []Polygon
+
+	// cross section area, for estimating resin usage
+	Area float64
+
+	plane   Plane
+	center  fauxgl.Vector
+	options RasterOptions
+}
+
+// slices the mesh along Z at the middle of each layer
+func RasterLayers(mesh *fauxgl.Mesh, options RasterOptions) ([]*RasterLayer, error) {
+	if options.LayerHeight <= 0 || options.PixelSize <= 0 || options.Width <= 0 || options.Height <= 0 {
+		return nil, errors.New("invalid raster options")
+	}
+	box := mesh.BoundingBox()
+	center := box.Center()
+	normal := fauxgl.Vector{0, 0, 1}
+	n := int(math.Ceil((box.Max.Z-box.Min.Z)/options.LayerHeight - 1e-9))
+	layers := make([]*RasterLayer, n)
+	for i := range layers {
+		z := box.Min.Z + (float64(i)+0.5)*options.LayerHeight
+		plane := MakePlane(fauxgl.Vector{0, 0, z}, normal)
+		polygons := Section(mesh, plane)
+		var area float64
+		for _, polygon := range polygons {
+			area += polygon.Area()
+		}
+		layers[i] = &RasterLayer{i + 1, z, polygons, area, plane, center, options}
+	}
+	return layers, nil
+}
+
+// renders the layer white on black with even-odd filling, X to the right
+// and Y up
+func (layer *RasterLayer) Image() *image.Gray {
+	o := layer.options
+	dc := gg.NewContext(o.Width, o.Height)
+	dc.SetRGB(0, 0, 0)
+	dc.Clear()
+	dc.SetFillRule(gg.FillRuleEvenOdd)
+	for _, polygon := range layer.Polygons {
+		for _, path := range polygon.paths() {
+			dc.NewSubPath()
+			for _, p := range path {
+				p = layer.plane.Unproject(p).Sub(layer.center)
+				x := float64(o.Width)/2 + p.X/o.PixelSize
+				y := float64(o.Height)/2 - p.Y/o.PixelSize
+				dc.LineTo(x, y)
+			}
+			dc.ClosePath()
+		}
+	}
+	dc.SetRGB(1, 1, 1)
+	dc.Fill()
+
+	src := dc.Image()
+	im := image.NewGray(src.Bounds())
+	for y := 0; y < o.Height; y++ {
+		for x := 0; x < o.Width; x++ {
+			r, _, _, _ := src.At(x, y).RGBA()
+			v := uint8(r >> 8)
+			if !o.Antialias {
+				if v >= 128 {
+					v = 255
+				} else {
+					v = 0
+				}
+			}
+			im.Pix[y*im.Stride+x] = v
+		}
+	}
+	return im
+}
+
+func (layer *RasterLayer) SavePNG(path string) error {
+	return gg.SavePNG(path, layer.Image())
+}
+
+// writes the layers as 1.png, 2.png, ... in a zip archive
+func WriteRasterZip(w io.Writer, layers []*RasterLayer) error {
+	zw := zip.NewWriter(w)
+	for _, layer := range layers {
+		f, err := zw.Create(fmt.Sprintf("%d.png", layer.Number))
+		if err != nil {
+			return err
+		}
+		if err := png.Encode(f, layer.Image()); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
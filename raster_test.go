@@ -0,0 +1,50 @@
+package choppy
+
+import (
+	"math"
+	"testing"
+
+	"github.com/fogleman/fauxgl"
+)
+
+func TestRasterLayers(t *testing.T) {
+	// a cube with a void in its middle third, its edges on pixel edges
+	mesh := testBox(fauxgl.Vector{}, fauxgl.Vector{30, 30, 30})
+	mesh.Add(testVoid(fauxgl.Vector{10, 10, 10}, fauxgl.Vector{20, 20, 20}))
+	layers, err := RasterLayers(mesh, RasterOptions{LayerHeight: 10, PixelSize: 1, Width: 40, Height: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(layers) != 3 {
+		t.Fatalf("%d layers, want 3", len(layers))
+	}
+	for i, want := range []float64{900, 800, 900} {
+		layer := layers[i]
+		if math.Abs(layer.Area-want) > 1e-9 {
+			t.Errorf("layer %d: area %g, want %g", layer.Number, layer.Area, want)
+		}
+		// the mesh spans pixels 5 to 35 across and 10 to 40 down, and the
+		// void is left unfilled
+		im := layer.Image()
+		var white float64
+		for y := 0; y < 50; y++ {
+			for x := 0; x < 40; x++ {
+				v := im.GrayAt(x, y).Y
+				inside := x >= 5 && x < 35 && y >= 10 && y < 40
+				if i == 1 && x >= 15 && x < 25 && y >= 20 && y < 30 {
+					inside = false
+				}
+				if (v == 255) != inside || (v != 0 && v != 255) {
+					t.Errorf("layer %d: pixel %d, %d is %d", layer.Number, x, y, v)
+				}
+				white += float64(v) / 255
+			}
+		}
+		if white != want {
+			t.Errorf("layer %d: %g white pixels, want %g", layer.Number, white, want)
+		}
+	}
+	if _, err := RasterLayers(mesh, RasterOptions{LayerHeight: 1}); err == nil {
+		t.Error("no resolution: no error")
+	}
+}
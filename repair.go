@@ -0,0 +1,368 @@
+package choppy
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/fogleman/fauxgl"
+)
+
+type RepairOptions struct {
+	// vertices closer than this are merged; 0 uses 1e-6 of the bounding box
+	// diagonal
+	WeldDistance float64
+
+	// boundary loops with at most this many edges are filled; 0 uses 16
+	MaxHoleEdges int
+}
+
+type RepairReport struct {
+	WeldedVertices  int
+	DegenerateFaces int
+	DuplicateFaces  int
+
+	// faces flipped to agree with their neighbors, and shells flipped so
+	// that outer shells face out and voids face in
+	FlippedFaces  int
+	FlippedShells int
+
+	FilledHoles int
+
+	// problems left after repair
+	BoundaryEdges    int
+	NonManifoldEdges int
+}
+
+func (r *RepairReport) Changed() bool {
+	return r.WeldedVertices > 0 || r.DegenerateFaces > 0 || r.DuplicateFaces > 0 ||
+		r.FlippedFaces > 0 || r.FlippedShells > 0 || r.FilledHoles > 0
+}
+
+func (r *RepairReport) String() string {
+	return fmt.Sprintf(
+		"%d welded vertices, %d degenerate faces, %d duplicate faces, %d flipped faces, %d flipped shells, %d filled holes, %d boundary edges, %d non-manifold edges",
+		r.WeldedVertices, r.DegenerateFaces, r.DuplicateFaces, r.FlippedFaces,
+		r.FlippedShells, r.FilledHoles, r.BoundaryEdges, r.NonManifoldEdges)
+}
+
+// returns a welded, cleaned and consistently oriented copy of the mesh
+func Repair(mesh *fauxgl.Mesh, options RepairOptions) (*fauxgl.Mesh, *RepairReport) {
+	if options.WeldDistance <= 0 {
+		options.WeldDistance = mesh.BoundingBox().Size().Length() * 1e-6
+	}
+	if options.MaxHoleEdges <= 0 {
+		options.MaxHoleEdges = 16
+	}
+	report := &RepairReport{}
+	r := &repairer{report: report, triangles: mesh.Triangles}
+	r.vertices, r.faces, report.WeldedVertices = weldVertices(mesh, options.WeldDistance)
+	r.sources = identity(len(r.faces))
+	r.removeBadFaces()
+	r.orient()
+	r.fillHoles(options.MaxHoleEdges)
+	r.count()
+	return r.mesh(), report
+}
+
+type repairer struct {
+	vertices []fauxgl.Vector
+	faces    [][3]int
+	report   *RepairReport
+
+	// triangle of the input each face came from, or -1 for filled holes
+	triangles []*fauxgl.Triangle
+	sources   []int
+}
+
+func (r *repairer) removeBadFaces() {
+	seen := make(map[[3]int]bool)
+	faces := r.faces[:0]
+	sources := r.sources[:0]
+	for i, f := range r.faces {
+		if f[0] == f[1] || f[1] == f[2] || f[2] == f[0] || r.normal(f) == (fauxgl.Vector{}) {
+			r.report.DegenerateFaces++
+			continue
+		}
+		k := sortedFace(f)
+		if seen[k] {
+			r.report.DuplicateFaces++
+			continue
+		}
+		seen[k] = true
+		faces = append(faces, f)
+		sources = append(sources, r.sources[i])
+	}
+	r.faces = faces
+	r.sources = sources
+}
+
+func (r *repairer) edgeFaces() map[edge][]int {
+	result := make(map[edge][]int)
+	for i, f := range r.faces {
+		for j := 0; j < 3; j++ {
+			e := sortedEdge(f[j], f[(j+1)%3])
+			result[e] = append(result[e], i)
+		}
+	}
+	return result
+}
+
+// makes faces agree with their neighbors across manifold edges, then
+// flips shells with the wrong volume sign
+func (r *repairer) orient() {
+	edges := r.edgeFaces()
+	flipped := make([]bool, len(r.faces))
+	flip := func(i int) {
+		f := r.faces[i]
+		r.faces[i] = [3]int{f[0], f[2], f[1]}
+		flipped[i] = !flipped[i]
+	}
+
+	shell := make([]int, len(r.faces))
+	for i := range shell {
+		shell[i] = -1
+	}
+	var shells [][]int
+	for i := range r.faces {
+		if shell[i] >= 0 {
+			continue
+		}
+		id := len(shells)
+		shell[i] = id
+		queue := []int{i}
+		for k := 0; k < len(queue); k++ {
+			f := r.faces[queue[k]]
+			for j := 0; j < 3; j++ {
+				a, b := f[j], f[(j+1)%3]
+				neighbors := edges[sortedEdge(a, b)]
+				if len(neighbors) != 2 {
+					continue
+				}
+				n := neighbors[0]
+				if n == queue[k] {
+					n = neighbors[1]
+				}
+				if shell[n] >= 0 {
+					continue
+				}
+				// a neighbor with the same directed edge faces the other way
+				if r.hasDirectedEdge(n, a, b) {
+					flip(n)
+				}
+				shell[n] = id
+				queue = append(queue, n)
+			}
+		}
+		shells = append(shells, queue)
+	}
+
+	boxes := make([]fauxgl.Box, len(shells))
+	for i, faces := range shells {
+		box := fauxgl.EmptyBox
+		for _, f := range faces {
+			for _, v := range r.faces[f] {
+				p := r.vertices[v]
+				box = box.Extend(fauxgl.Box{p, p})
+			}
+		}
+		boxes[i] = box
+	}
+
+	for i, faces := range shells {
+		var volume float64
+		for _, f := range faces {
+			volume += r.signedVolume(r.faces[f])
+		}
+		// shells inside an odd number of other shells are voids
+		depth := 0
+		p := r.vertices[r.faces[faces[0]][0]]
+		for j, other := range shells {
+			if i != j && boxes[j].Contains(p) && r.contains(other, p) {
+				depth++
+			}
+		}
+		void := depth%2 == 1
+		if (volume < 0) != void {
+			for _, f := range faces {
+				flip(f)
+			}
+		}
+		count := 0
+		for _, f := range faces {
+			if flipped[f] {
+				count++
+			}
+		}
+		if count == len(faces) {
+			r.report.FlippedShells++
+		} else {
+			r.report.FlippedFaces += count
+		}
+	}
+}
+
+// fills boundary loops of at most maxEdges edges with a fan around their
+// centroid
+func (r *repairer) fillHoles(maxEdges int) {
+	directed := make(map[edge]bool)
+	for _, f := range r.faces {
+		for j := 0; j < 3; j++ {
+			directed[edge{f[j], f[(j+1)%3]}] = true
+		}
+	}
+	next := make(map[int]int)
+	ambiguous := make(map[int]bool)
+	for e := range directed {
+		if directed[edge{e.B, e.A}] {
+			continue
+		}
+		if _, ok := next[e.A]; ok {
+			ambiguous[e.A] = true
+		}
+		next[e.A] = e.B
+	}
+	visited := make(map[int]bool)
+	for _, start := range sortedKeys(next) {
+		if visited[start] {
+			continue
+		}
+		var loop []int
+		ok := true
+		for v := start; ; {
+			if ambiguous[v] || visited[v] {
+				ok = false
+				break
+			}
+			visited[v] = true
+			loop = append(loop, v)
+			w, found := next[v]
+			if !found {
+				ok = false
+				break
+			}
+			if w == start {
+				break
+			}
+			v = w
+		}
+		if !ok || len(loop) < 3 || len(loop) > maxEdges {
+			continue
+		}
+		if len(loop) == 3 {
+			r.faces = append(r.faces, [3]int{loop[2], loop[1], loop[0]})
+			r.sources = append(r.sources, -1)
+		} else {
+			var c fauxgl.Vector
+			for _, v := range loop {
+				c = c.Add(r.vertices[v])
+			}
+			ci := len(r.vertices)
+			r.vertices = append(r.vertices, c.DivScalar(float64(len(loop))))
+			for i, a := range loop {
+				b := loop[(i+1)%len(loop)]
+				r.faces = append(r.faces, [3]int{ci, b, a})
+				r.sources = append(r.sources, -1)
+			}
+		}
+		r.report.FilledHoles++
+	}
+}
+
+func (r *repairer) count() {
+	m := NewIndexedMesh(r.vertices, r.faces)
+	r.report.BoundaryEdges = m.BoundaryEdges()
+	r.report.NonManifoldEdges = m.NonManifoldEdges()
+}
+
+func (r *repairer) mesh() *fauxgl.Mesh {
+	m := NewIndexedMesh(r.vertices, r.faces)
+	m.triangles, m.sources = r.triangles, r.sources
+	return m.Mesh()
+}
+
+func (r *repairer) hasDirectedEdge(face, a, b int) bool {
+	f := r.faces[face]
+	for j := 0; j < 3; j++ {
+		if f[j] == a && f[(j+1)%3] == b {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *repairer) normal(f [3]int) fauxgl.Vector {
+	p1, p2, p3 := r.vertices[f[0]], r.vertices[f[1]], r.vertices[f[2]]
+	return p2.Sub(p1).Cross(p3.Sub(p1))
+}
+
+func (r *repairer) signedVolume(f [3]int) float64 {
+	p1, p2, p3 := r.vertices[f[0]], r.vertices[f[1]], r.vertices[f[2]]
+	return p1.Dot(p2.Cross(p3)) / 6
+}
+
+// ray parity test of p against the faces of a shell
+func (r *repairer) contains(faces []int, p fauxgl.Vector) bool {
+	inside := false
+	for _, i := range faces {
+		f := r.faces[i]
+		if rayHitsTriangle(p, rayDirection, r.vertices[f[0]], r.vertices[f[1]], r.vertices[f[2]]) {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// an irregular direction to avoid hitting edges of axis aligned meshes
+var rayDirection = fauxgl.Vector{0.5773, 0.5774, 0.5775}.Normalize()
+
+func rayHitsTriangle(o, d, v1, v2, v3 fauxgl.Vector) bool {
+	_, ok := rayTriangle(o, d, v1, v2, v3)
+	return ok
+}
+
+// distance along d from o to the triangle, in lengths of d
+func rayTriangle(o, d, v1, v2, v3 fauxgl.Vector) (float64, bool) {
+	e1 := v2.Sub(v1)
+	e2 := v3.Sub(v1)
+	p := d.Cross(e2)
+	det := e1.Dot(p)
+	if math.Abs(det) <= 1e-12*e1.Length()*e2.Length() {
+		return 0, false
+	}
+	inv := 1 / det
+	s := o.Sub(v1)
+	u := s.Dot(p) * inv
+	if u < 0 || u > 1 {
+		return 0, false
+	}
+	q := s.Cross(e1)
+	v := d.Dot(q) * inv
+	if v < 0 || u+v > 1 {
+		return 0, false
+	}
+	t := e2.Dot(q) * inv
+	return t, t > 0
+}
+
+func sortedFace(f [3]int) [3]int {
+	if f[0] > f[1] {
+		f[0], f[1] = f[1], f[0]
+	}
+	if f[1] > f[2] {
+		f[1], f[2] = f[2], f[1]
+	}
+	if f[0] > f[1] {
+		f[0], f[1] = f[1], f[0]
+	}
+	return f
+}
+
+func sortedKeys(m map[int]int) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
@@ -0,0 +1,133 @@
+package choppy
+
+import (
+	"testing"
+
+	"github.com/fogleman/fauxgl"
+)
+
+// cube with a color, texture coordinates and outward normals at every vertex
+func testPaintedBox(min, max fauxgl.Vector, color fauxgl.Color) *fauxgl.Mesh {
+	m := testBox(min, max)
+	for _, t := range m.Triangles {
+		n := t.Normal()
+		for _, v := range []*fauxgl.Vertex{&t.V1, &t.V2, &t.V3} {
+			v.Normal = n
+			v.Color = color
+			v.Texture = fauxgl.Vector{v.Position.X, v.Position.Y, 0}
+		}
+	}
+	return m
+}
+
+func checkAttributes(t *testing.T, name string, m *fauxgl.Mesh, color fauxgl.Color) {
+	t.Helper()
+	for _, tri := range m.Triangles {
+		n := tri.Normal()
+		for _, v := range []fauxgl.Vertex{tri.V1, tri.V2, tri.V3} {
+			if v.Color != color {
+				t.Errorf("%s: color %v, want %v", name, v.Color, color)
+				return
+			}
+			if v.Normal.Dot(n) < 0.999 {
+				t.Errorf("%s: normal %v on a face facing %v", name, v.Normal, n)
+				return
+			}
+			want := fauxgl.Vector{v.Position.X, v.Position.Y, 0}
+			if v.Texture.Sub(want).Length() > 1e-9 {
+				t.Errorf("%s: texture %v at %v", name, v.Texture, v.Position)
+				return
+			}
+		}
+	}
+}
+
+func TestRepair(t *testing.T) {
+	red := fauxgl.Color{1, 0, 0, 1}
+	cube := testPaintedBox(fauxgl.Vector{}, fauxgl.Vector{20, 20, 20}, red)
+
+	// one face turned inside out and a duplicate of another
+	flipped := cube.Copy()
+	f := flipped.Triangles[3]
+	f.V1, f.V2 = f.V2, f.V1
+	duplicate := *flipped.Triangles[5]
+	flipped.Triangles = append(flipped.Triangles, &duplicate)
+	m, report := Repair(flipped, RepairOptions{})
+	checkClosed(t, "flipped", m, 8000)
+	checkAttributes(t, "flipped", m, red)
+	if report.FlippedFaces != 1 || report.DuplicateFaces != 1 {
+		t.Errorf("flipped: %+v", report)
+	}
+
+	// filled holes have no triangle to take attributes from
+	holed := fauxgl.NewTriangleMesh(cube.Copy().Triangles[1:])
+	m, report = Repair(holed, RepairOptions{})
+	checkClosed(t, "holed", m, 8000)
+	if report.FilledHoles != 1 {
+		t.Errorf("holed: %+v", report)
+	}
+}
+
+func TestChopIndexedAttributes(t *testing.T) {
+	red := fauxgl.Color{1, 0, 0, 1}
+	cube := testPaintedBox(fauxgl.Vector{}, fauxgl.Vector{20, 20, 20}, red)
+	plane := MakePlane(fauxgl.Vector{0, 0, 7.3}, fauxgl.Vector{0, 0, 1})
+	m := plane.ChopIndexed(cube)
+	if !m.IsClosed() {
+		t.Fatalf("%d boundary edges", m.BoundaryEdges())
+	}
+	// the cap is new, the sides keep their paint
+	var sides []*fauxgl.Triangle
+	for _, tri := range m.Mesh().Triangles {
+		if tri.Normal().Z > -0.5 {
+			sides = append(sides, tri)
+		}
+	}
+	checkAttributes(t, "sides", fauxgl.NewTriangleMesh(sides), red)
+}
+
+func TestChopCapAttributes(t *testing.T) {
+	red := fauxgl.Color{1, 0, 0, 1}
+	blue := fauxgl.Color{0, 0, 1, 1}
+	cube := testPaintedBox(fauxgl.Vector{}, fauxgl.Vector{20, 20, 20}, red)
+	plane := MakePlane(fauxgl.Vector{10, 10, 10}, fauxgl.Vector{1, 2, 3})
+	front, back, err := plane.ChopWith(cube, Options{CapColor: blue})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the cap of each part faces out of it, and the texture coordinates of
+	// both caps are their positions along the plane's U and V axes, so the
+	// two match across the cut
+	n := plane.Normal.Normalize()
+	for _, part := range []struct {
+		name   string
+		mesh   *fauxgl.Mesh
+		normal fauxgl.Vector
+	}{{"front", front, n.Negate()}, {"back", back, n}} {
+		var caps, sides []*fauxgl.Triangle
+		for _, tri := range part.mesh.Triangles {
+			if tri.Normal().Dot(part.normal) > 0.999 {
+				caps = append(caps, tri)
+			} else {
+				sides = append(sides, tri)
+			}
+		}
+		if len(caps) == 0 {
+			t.Errorf("%s: no cap", part.name)
+		}
+		for _, tri := range caps {
+			for _, v := range []fauxgl.Vertex{tri.V1, tri.V2, tri.V3} {
+				if v.Color != blue {
+					t.Errorf("%s: cap color %v, want %v", part.name, v.Color, blue)
+				}
+				if v.Normal.Sub(part.normal).Length() > 1e-12 {
+					t.Errorf("%s: cap normal %v, want %v", part.name, v.Normal, part.normal)
+				}
+				if want := plane.Project(v.Position); v.Texture.Sub(want).Length() > 1e-9 {
+					t.Errorf("%s: cap texture %v at %v, want %v", part.name, v.Texture, v.Position, want)
+				}
+			}
+		}
+		checkAttributes(t, part.name+" sides", fauxgl.NewTriangleMesh(sides), red)
+	}
+}
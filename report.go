@@ -0,0 +1,73 @@
+package choppy
+
+import (
+	"fmt"
+
+	"github.com/fogleman/fauxgl"
+)
+
+type Report struct {
+	ClosedLoops int
+
+	// chains that did not close, from their first to their last point
+	OpenChains []Path
+
+	// closed loops with fewer than 3 points
+	DroppedLoops int
+
+	DegenerateTriangles int
+
+	// polygons that produced no cap triangles
+	EmptyCaps int
+
+	NestingDepth int
+	CapArea      float64
+}
+
+// reports whether every loop of the cross section was closed and capped
+func (r *Report) Closed() bool {
+	return len(r.OpenChains) == 0 && r.DroppedLoops == 0 && r.EmptyCaps == 0
+}
+
+// the methods below do nothing on a nil report, so chops without one can
+// pass it along
+
+func (r *Report) countDegenerate(m *fauxgl.Mesh) {
+	if r == nil {
+		return
+	}
+	for _, t := range m.Triangles {
+		if t.IsDegenerate() {
+			r.DegenerateTriangles++
+		}
+	}
+}
+
+// report for a back cap, to be merged with addBack
+func (r *Report) back() *Report {
+	if r == nil {
+		return nil
+	}
+	return &Report{}
+}
+
+// adds the failures of the back cap, which otherwise repeats the front
+func (r *Report) addBack(back *Report) {
+	if r == nil || back == nil {
+		return
+	}
+	r.OpenChains = append(r.OpenChains, back.OpenChains...)
+	r.DroppedLoops += back.DroppedLoops
+	r.EmptyCaps += back.EmptyCaps
+}
+
+func (r *Report) String() string {
+	return fmt.Sprintf(
+		"%d closed loops, %d open chains, %d dropped loops, %d degenerate triangles, %d empty caps, nesting depth %d, cap area %g",
+		r.ClosedLoops, len(r.OpenChains), r.DroppedLoops, r.DegenerateTriangles,
+		r.EmptyCaps, r.NestingDepth, r.CapArea)
+}
+
+func nestingDepth(paths []Path) int {
+	return newPathTree(paths).maxDepth()
+}
@@ -0,0 +1,44 @@
+package choppy
+
+import (
+	"testing"
+
+	"github.com/fogleman/fauxgl"
+)
+
+func TestReportClosed(t *testing.T) {
+	cube := testBox(fauxgl.Vector{}, fauxgl.Vector{20, 20, 20})
+	// without one side face the cross section is an open chain
+	open := fauxgl.NewTriangleMesh(cube.Triangles[:10])
+	// through three corners, so the back cap differs
+	touching := MakePlane(fauxgl.Vector{20, 0, 0}, fauxgl.Vector{1, 1, 1})
+	plane := MakePlane(fauxgl.Vector{10, 10, 10}, fauxgl.Vector{0, 0, 1})
+
+	reports := func(m *fauxgl.Mesh, p Plane) []*Report {
+		_, a := p.ChopReport(m)
+		_, _, b := p.ChopBothReport(m)
+		_, _, c, err := p.ChopWithReport(m, Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return []*Report{a, b, c}
+	}
+	for i, r := range reports(cube, plane) {
+		if !r.Closed() || r.ClosedLoops != 1 || r.CapArea != 400 {
+			t.Errorf("cube report %d: %v", i, r)
+		}
+	}
+	for i, r := range reports(cube, touching) {
+		if !r.Closed() {
+			t.Errorf("touching report %d: %v", i, r)
+		}
+	}
+	for i, r := range reports(open, plane) {
+		if r.Closed() || len(r.OpenChains) == 0 {
+			t.Errorf("open report %d: %v", i, r)
+		}
+	}
+	if r := (&Report{DroppedLoops: 1}); r.Closed() {
+		t.Error("report with a dropped loop is closed")
+	}
+}
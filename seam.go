@@ -0,0 +1,227 @@
+package choppy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+
+	"github.com/fogleman/fauxgl"
+)
+
+// curve in the U/V coordinates of a plane running across the mesh along U;
+// swept along the plane normal it splits the mesh into the part above it
+// along V and the part below it
+type SeamProfile struct {
+	// zigzag, sine or jigsaw, repeating every Period along U and reaching
+	// Amplitude to either side of V = 0; empty uses Points
+	Shape     string
+	Amplitude float64
+	Period    float64
+
+	// polyline as U, V pairs, continued straight along U past its ends
+	Points [][2]float64
+
+	// passes a Catmull-Rom spline through the points instead
+	Smooth bool
+}
+
+func LoadSeamProfile(path string) (SeamProfile, error) {
+	var profile SeamProfile
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return profile, err
+	}
+	err = json.Unmarshal(data, &profile)
+	return profile, err
+}
+
+// polyline of the profile covering u0 to u1; built-in shapes keep their
+// phase at U = 0
+func (s SeamProfile) Path(u0, u1 float64) (Path, error) {
+	if s.Shape == "" {
+		if len(s.Points) < 2 {
+			return nil, errors.New("seam profile needs at least two points")
+		}
+		path := make(Path, len(s.Points))
+		for i, p := range s.Points {
+			path[i] = fauxgl.Vector{p[0], p[1], 0}
+		}
+		if s.Smooth {
+			path = catmullRom(path, 8)
+		}
+		return path, nil
+	}
+	if s.Period <= 0 || s.Amplitude <= 0 {
+		return nil, errors.New("seam profile needs a positive amplitude and period")
+	}
+	k0 := int(math.Floor(u0 / s.Period))
+	k1 := int(math.Ceil(u1 / s.Period))
+	if k1 <= k0 {
+		k1 = k0 + 1
+	}
+	var path Path
+	for k := k0; k < k1; k++ {
+		var period Path
+		switch s.Shape {
+		case "zigzag":
+			period = zigzagPeriod(s.Amplitude, s.Period)
+		case "sine":
+			period = sinePeriod(s.Amplitude, s.Period)
+		case "jigsaw":
+			// tabs alternate sides so both parts hold each other
+			period = jigsawPeriod(s.Amplitude, s.Period, k%2 == 0)
+		default:
+			return nil, fmt.Errorf("unknown seam shape: %s", s.Shape)
+		}
+		u := float64(k) * s.Period
+		for _, p := range period {
+			path = append(path, fauxgl.Vector{u + p.X, p.Y, 0})
+		}
+	}
+	last := float64(k1) * s.Period
+	return append(path, fauxgl.Vector{last, 0, 0}), nil
+}
+
+// one period of each shape from U = 0, without the point at U = period
+
+func zigzagPeriod(amplitude, period float64) Path {
+	return Path{
+		{0, 0, 0},
+		{period / 4, amplitude, 0},
+		{period * 3 / 4, -amplitude, 0},
+	}
+}
+
+func sinePeriod(amplitude, period float64) Path {
+	const n = 24
+	path := make(Path, n)
+	for i := range path {
+		u := period * float64(i) / n
+		path[i] = fauxgl.Vector{u, amplitude * math.Sin(2*math.Pi*u/period), 0}
+	}
+	return path
+}
+
+// flat with a round tab on a narrower neck in the middle, reaching amplitude
+// from V = 0
+func jigsawPeriod(amplitude, period float64, up bool) Path {
+	const n = 24
+	r := math.Min(period/5, amplitude/2)
+	w := r / 2
+	h := math.Sqrt(r*r - w*w)
+	c := period / 2
+	sign := 1.0
+	if !up {
+		sign = -1
+	}
+	path := Path{{0, 0, 0}, {c - w, 0, 0}}
+	// around the head from the left of the neck over the top to its right
+	a0 := math.Atan2(-h, -w) + 2*math.Pi
+	a1 := math.Atan2(-h, w)
+	for i := 0; i <= n; i++ {
+		a := a0 + (a1-a0)*float64(i)/n
+		x := c + r*math.Cos(a)
+		y := amplitude - r + r*math.Sin(a)
+		path = append(path, fauxgl.Vector{x, sign * y, 0})
+	}
+	return append(path, fauxgl.Vector{c + w, 0, 0})
+}
+
+// uniform Catmull-Rom spline through the points, n segments between each pair
+func catmullRom(points Path, n int) Path {
+	at := func(i int) fauxgl.Vector {
+		return points[clampInt(i, 0, len(points)-1)]
+	}
+	var result Path
+	for i := 0; i < len(points)-1; i++ {
+		p0, p1, p2, p3 := at(i-1), at(i), at(i+1), at(i+2)
+		for j := 0; j < n; j++ {
+			t := float64(j) / float64(n)
+			t2 := t * t
+			t3 := t2 * t
+			p := p1.MulScalar(2).
+				Add(p2.Sub(p0).MulScalar(t)).
+				Add(p0.MulScalar(2).Sub(p1.MulScalar(5)).Add(p2.MulScalar(4)).Sub(p3).MulScalar(t2)).
+				Add(p1.MulScalar(3).Sub(p0).Sub(p2.MulScalar(3)).Add(p3).MulScalar(t3)).
+				MulScalar(0.5)
+			result = append(result, p)
+		}
+	}
+	return append(result, points[len(points)-1])
+}
+
+// splits the mesh along the profile swept along the normal, returning the
+// parts above and below it along V; both are closed and fit into each other
+func (p Plane) ChopSeam(m *fauxgl.Mesh, profile SeamProfile) (above, below *fauxgl.Mesh, err error) {
+	if len(m.Triangles) == 0 {
+		return fauxgl.NewEmptyMesh(), fauxgl.NewEmptyMesh(), nil
+	}
+	box := p.meshBox(m)
+	margin := box.Size().Length()*0.01 + profile.Amplitude
+	u0 := box.Min.X - margin
+	u1 := box.Max.X + margin
+	path, err := profile.Path(u0, u1)
+	if err != nil {
+		return nil, nil, err
+	}
+	if path[0].X > path[len(path)-1].X {
+		path = reversePath(path)
+	}
+
+	// close the profile below the mesh and sweep it past both ends
+	low := box.Min.Y
+	for _, q := range path {
+		low = math.Min(low, q.Y)
+	}
+	low -= margin
+	var polygon Path
+	if first := path[0]; first.X > u0 {
+		polygon = append(polygon, fauxgl.Vector{u0, first.Y, 0})
+	}
+	polygon = append(polygon, path...)
+	if last := path[len(path)-1]; last.X < u1 {
+		polygon = append(polygon, fauxgl.Vector{u1, last.Y, 0})
+	}
+	polygon = append(polygon,
+		fauxgl.Vector{polygon[len(polygon)-1].X, low, 0},
+		fauxgl.Vector{polygon[0].X, low, 0})
+	cutter := p.prism(polygon, box.Min.Z-margin, box.Max.Z+margin)
+
+	below, above, err = ChopWithMesh(m, cutter)
+	if err != nil {
+		return nil, nil, err
+	}
+	return above, below, nil
+}
+
+// closed mesh of the polygon in U/V swept along the normal from w0 to w1
+func (p Plane) prism(polygon Path, w0, w1 float64) *fauxgl.Mesh {
+	if polygon.SignedArea() < 0 {
+		polygon = reversePath(polygon)
+	}
+	normal := p.Normal.Normalize()
+	point := func(q fauxgl.Vector, w float64) fauxgl.Vector {
+		return p.Unproject(q).Add(normal.MulScalar(w))
+	}
+
+	// counterclockwise in U/V faces against the normal
+	var triangles []*fauxgl.Triangle
+	_, faces := triangulate([]Path{polygon})
+	for _, f := range faces {
+		a, b, c := polygon[f[0]], polygon[f[1]], polygon[f[2]]
+		triangles = append(triangles,
+			fauxgl.NewTriangleForPoints(point(a, w0), point(b, w0), point(c, w0)),
+			fauxgl.NewTriangleForPoints(point(a, w1), point(c, w1), point(b, w1)))
+	}
+	for i, a := range polygon {
+		b := polygon[(i+1)%len(polygon)]
+		a0, a1 := point(a, w0), point(a, w1)
+		b0, b1 := point(b, w0), point(b, w1)
+		triangles = append(triangles,
+			fauxgl.NewTriangleForPoints(a0, a1, b1),
+			fauxgl.NewTriangleForPoints(a0, b1, b0))
+	}
+	return fauxgl.NewTriangleMesh(triangles)
+}
@@ -0,0 +1,106 @@
+package choppy
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fogleman/fauxgl"
+)
+
+func TestChopSeam(t *testing.T) {
+	cube := testBox(fauxgl.Vector{}, fauxgl.Vector{20, 20, 20})
+	sphere := testSphere(fauxgl.Vector{}, 10, 16)
+	cylinder := testCylinder(10, 0, 20, 24)
+	z := fauxgl.Vector{0, 0, 1}
+	x := fauxgl.Vector{1, 0, 0}
+	// the profiles turn into themselves a half turn about the point, so
+	// meshes that do too split in half
+	cases := []struct {
+		name  string
+		mesh  *fauxgl.Mesh
+		plane Plane
+	}{
+		{"cube through center", cube, MakePlaneAlong(fauxgl.Vector{10, 10, 10}, z, x)},
+		// the profile through the poles and the cap centers
+		{"sphere poles", sphere, MakePlaneAlong(fauxgl.Vector{}, z, x)},
+		{"cylinder fan centers", cylinder, MakePlaneAlong(fauxgl.Vector{0, 0, 10}, z, x)},
+	}
+	profiles := []SeamProfile{
+		{Shape: "zigzag", Amplitude: 3, Period: 20},
+		{Shape: "sine", Amplitude: 3, Period: 20},
+		{Shape: "jigsaw", Amplitude: 4, Period: 20},
+	}
+	for _, c := range cases {
+		for _, profile := range profiles {
+			name := c.name + " " + profile.Shape
+			above, below, err := c.plane.ChopSeam(c.mesh, profile)
+			if err != nil {
+				t.Errorf("%s: %v", name, err)
+				continue
+			}
+			volume := c.mesh.Volume()
+			checkCut(t, name, above, below, volume/2, volume)
+		}
+	}
+}
+
+func TestChopSeamPoints(t *testing.T) {
+	cube := testBox(fauxgl.Vector{}, fauxgl.Vector{20, 20, 20})
+	plane := MakePlaneAlong(fauxgl.Vector{10, 10, 10}, fauxgl.Vector{0, 0, 1}, fauxgl.Vector{1, 0, 0})
+
+	// the spline runs through the points and keeps their half turn symmetry
+	smooth := SeamProfile{Points: [][2]float64{{-10, 0}, {-5, 4}, {0, 0}, {5, -4}, {10, 0}}, Smooth: true}
+	path, err := smooth.Path(-10, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, p := range smooth.Points {
+		if q := path[i*8]; q != (fauxgl.Vector{p[0], p[1], 0}) {
+			t.Errorf("smooth: point %d at %v, want %v", i, q, p)
+		}
+	}
+	above, below, err := plane.ChopSeam(cube, smooth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkCut(t, "smooth", above, below, 4000, 8000)
+
+	// a trapezoid of area 60 rising into the part above
+	file := filepath.Join(t.TempDir(), "profile.json")
+	data := `{"Points": [[-10, 0], [-5, 4], [5, 4], [10, 0]]}`
+	if err := os.WriteFile(file, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	profile, err := LoadSeamProfile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(profile.Points) != 4 || profile.Points[1] != [2]float64{-5, 4} || profile.Shape != "" || profile.Smooth {
+		t.Errorf("loaded %+v", profile)
+	}
+	above, below, err = plane.ChopSeam(cube, profile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkCut(t, "loaded", above, below, (200-60)*20, 8000)
+
+	if _, err := LoadSeamProfile(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("missing file: no error")
+	}
+}
+
+func TestMakePlaneAlong(t *testing.T) {
+	x := fauxgl.Vector{1, 0, 0}
+	p := MakePlaneAlong(fauxgl.Vector{}, fauxgl.Vector{0, 0, 2}, fauxgl.Vector{1, 0, 1})
+	if p.U.Sub(x).Length() > 1e-12 || math.Abs(p.V.Dot(p.Normal)) > 1e-12 {
+		t.Errorf("along: U %v, V %v", p.U, p.V)
+	}
+	// along the normal
+	p = MakePlaneAlong(fauxgl.Vector{}, x, x)
+	q := MakePlane(fauxgl.Vector{}, x)
+	if p.U != q.U || p.V != q.V {
+		t.Errorf("along the normal: U %v, V %v", p.U, p.V)
+	}
+}
@@ -0,0 +1,165 @@
+package choppy
+
+import (
+	"math"
+
+	"github.com/fogleman/fauxgl"
+)
+
+type Cost int
+
+const (
+	// smallest cross section area
+	AreaCost Cost = iota
+
+	// fewest cross section loops
+	LoopsCost
+
+	// largest minimum wall thickness of the cross section, see
+	// Polygon.Thickness
+	ThicknessCost
+
+	// most balanced volumes on either side of the plane
+	BalanceCost
+)
+
+type SearchOptions struct {
+	Cost Cost
+
+	// plane normals to try; if empty, Samples directions spread over a hemisphere
+	Directions []fauxgl.Vector
+	Samples    int
+
+	// number of offsets tried per direction, spread between Min and Max,
+	// given as fractions of the mesh extent along the direction
+	Steps    int
+	Min, Max float64
+}
+
+type Candidate struct {
+	Plane Plane
+
+	// distance of the plane from the lowest point of the mesh along its normal
+	Offset float64
+
+	// +Inf when the plane misses the mesh
+	Cost float64
+}
+
+type SearchResult struct {
+	Best Candidate
+
+	// every candidate, ordered by direction then offset
+	Candidates []Candidate
+}
+
+func Search(mesh *fauxgl.Mesh, options SearchOptions) SearchResult {
+	directions := options.Directions
+	if len(directions) == 0 {
+		samples := options.Samples
+		if samples <= 0 {
+			samples = 64
+		}
+		directions = hemisphere(samples)
+	}
+	steps := options.Steps
+	if steps <= 0 {
+		steps = 32
+	}
+	t0, t1 := options.Min, options.Max
+	if t1 == 0 {
+		t1 = 1
+	}
+
+	var result SearchResult
+	result.Best.Cost = math.Inf(1)
+	for _, normal := range directions {
+		normal = normal.Normalize()
+		lo, hi := extent(mesh, normal)
+		for i := 0; i < steps; i++ {
+			t := t0 + (t1-t0)*(float64(i)+0.5)/float64(steps)
+			offset := (hi - lo) * t
+			plane := MakePlane(normal.MulScalar(lo+offset), normal)
+			c := Candidate{plane, offset, plane.cost(mesh, options.Cost)}
+			result.Candidates = append(result.Candidates, c)
+			if c.Cost < result.Best.Cost {
+				result.Best = c
+			}
+		}
+	}
+	return result
+}
+
+func (p Plane) cost(m *fauxgl.Mesh, cost Cost) float64 {
+	if cost == BalanceCost {
+		// caps lie in the plane, so volumes about a point in the plane
+		// only need the clipped walls
+		s := p.cut(m, Options{}).splitMesh(m)
+		v1 := volumeAbout(s.Front, p.Point)
+		v2 := volumeAbout(s.Back, p.Point)
+		if v1 <= 0 || v2 <= 0 {
+			return math.Inf(1)
+		}
+		return math.Abs(v1-v2) / (v1 + v2)
+	}
+	polygons := p.slicePolygons(m, nil)
+	if len(polygons) == 0 {
+		return math.Inf(1)
+	}
+	var result float64
+	switch cost {
+	case AreaCost:
+		for _, polygon := range polygons {
+			result += polygon.Area()
+		}
+	case LoopsCost:
+		for _, polygon := range polygons {
+			result += float64(1 + len(polygon.Interiors))
+		}
+	case ThicknessCost:
+		result = math.Inf(1)
+		for _, polygon := range polygons {
+			result = math.Min(result, polygon.Thickness())
+		}
+		result = -result
+	}
+	return result
+}
+
+func volumeAbout(m *fauxgl.Mesh, origin fauxgl.Vector) float64 {
+	var result float64
+	for _, t := range m.Triangles {
+		p1 := t.V1.Position.Sub(origin)
+		p2 := t.V2.Position.Sub(origin)
+		p3 := t.V3.Position.Sub(origin)
+		result += p1.Dot(p2.Cross(p3))
+	}
+	return result / 6
+}
+
+// range of the mesh vertices projected onto a direction
+func extent(m *fauxgl.Mesh, direction fauxgl.Vector) (float64, float64) {
+	lo := math.Inf(1)
+	hi := math.Inf(-1)
+	for _, t := range m.Triangles {
+		for _, v := range []fauxgl.Vector{t.V1.Position, t.V2.Position, t.V3.Position} {
+			d := v.Dot(direction)
+			lo = math.Min(lo, d)
+			hi = math.Max(hi, d)
+		}
+	}
+	return lo, hi
+}
+
+// directions spread evenly over the upper hemisphere
+func hemisphere(n int) []fauxgl.Vector {
+	golden := math.Pi * (3 - math.Sqrt(5))
+	result := make([]fauxgl.Vector, n)
+	for i := range result {
+		z := 1 - (float64(i)+0.5)/float64(n)
+		r := math.Sqrt(1 - z*z)
+		a := golden * float64(i)
+		result[i] = fauxgl.Vector{math.Cos(a) * r, math.Sin(a) * r, z}
+	}
+	return result
+}
@@ -0,0 +1,24 @@
+package choppy
+
+import "github.com/fogleman/fauxgl"
+
+// cross section of the mesh in the plane's U/V coordinates
+func Section(mesh *fauxgl.Mesh, plane Plane) []Polygon {
+	return plane.slicePolygons(mesh, nil)
+}
+
+func polygonsBoundingBox(polygons []Polygon) fauxgl.Box {
+	box := fauxgl.EmptyBox
+	for _, polygon := range polygons {
+		box = box.Extend(polygon.Exterior.BoundingBox())
+	}
+	return box
+}
+
+// exterior followed by interiors, each a separate closed path
+func (polygon Polygon) paths() []Path {
+	paths := make([]Path, len(polygon.Interiors)+1)
+	paths[0] = polygon.Exterior
+	copy(paths[1:], polygon.Interiors)
+	return paths
+}
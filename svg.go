@@ -0,0 +1,72 @@
+package choppy
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/fogleman/fauxgl"
+)
+
+type label struct {
+	Text     string
+	Position fauxgl.Vector
+	Size     float64
+}
+
+func SaveSVG(path string, polygons []Polygon) error {
+	return saveSVG(path, polygons, nil, polygonsBoundingBox(polygons))
+}
+
+func WriteSVG(w io.Writer, polygons []Polygon) error {
+	return writeSVG(w, polygons, nil, polygonsBoundingBox(polygons))
+}
+
+func saveSVG(path string, polygons []Polygon, labels []label, box fauxgl.Box) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return writeSVG(file, polygons, labels, box)
+}
+
+// writes polygons in millimeters with y pointing up, each exterior and
+// interior as its own closed path, with box as the page
+func writeSVG(w io.Writer, polygons []Polygon, labels []label, box fauxgl.Box) error {
+	size := box.Size()
+	if size.X <= 0 || size.Y <= 0 {
+		// an empty section still needs a page, as viewers draw nothing for
+		// a zero width or height
+		box = box.Offset(0.5)
+		size = box.Size()
+	}
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, `<?xml version="1.0" encoding="UTF-8"?>`)
+	fmt.Fprintf(bw,
+		`<svg xmlns="http://www.w3.org/2000/svg" width="%gmm" height="%gmm" viewBox="0 0 %g %g">`+"\n",
+		size.X, size.Y, size.X, size.Y)
+	fmt.Fprintln(bw, `<g fill="none" stroke="black" stroke-width="0.1">`)
+	for _, polygon := range polygons {
+		for _, path := range polygon.paths() {
+			fmt.Fprint(bw, `<path d="`)
+			for i, p := range path {
+				command := "L"
+				if i == 0 {
+					command = "M"
+				}
+				fmt.Fprintf(bw, "%s%g %g ", command, p.X-box.Min.X, box.Max.Y-p.Y)
+			}
+			fmt.Fprintln(bw, `Z"/>`)
+		}
+	}
+	fmt.Fprintln(bw, "</g>")
+	for _, l := range labels {
+		fmt.Fprintf(bw,
+			`<text x="%g" y="%g" font-size="%g" text-anchor="middle" dominant-baseline="central" fill="blue">%s</text>`+"\n",
+			l.Position.X-box.Min.X, box.Max.Y-l.Position.Y, l.Size, l.Text)
+	}
+	fmt.Fprintln(bw, "</svg>")
+	return bw.Flush()
+}
@@ -0,0 +1,46 @@
+package choppy
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteSVG(t *testing.T) {
+	square := Polygon{Path{{0, 0, 0}, {20, 0, 0}, {20, 10, 0}, {0, 10, 0}}, nil}
+	cases := []struct {
+		name     string
+		polygons []Polygon
+		size     string
+	}{
+		{"square", []Polygon{square}, `width="20mm" height="10mm"`},
+		{"empty", nil, `width="1mm" height="1mm"`},
+	}
+	for _, c := range cases {
+		var b bytes.Buffer
+		if err := WriteSVG(&b, c.polygons); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(b.String(), c.size) {
+			t.Errorf("%s: no %s in %s", c.name, c.size, b.String())
+		}
+	}
+}
+
+func TestWriteDXF(t *testing.T) {
+	square := Polygon{Path{{0, 0, 0}, {20, 0, 0}, {20, 10, 0}, {0, 10, 0}}, nil}
+	var b bytes.Buffer
+	if err := WriteDXF(&b, []Polygon{square}); err != nil {
+		t.Fatal(err)
+	}
+	dxf := b.String()
+	if !strings.Contains(dxf, "$ACADVER\n1\nAC1009\n") {
+		t.Error("not an R12 file")
+	}
+	if strings.Contains(dxf, "$INSUNITS") {
+		t.Error("R12 header has $INSUNITS")
+	}
+	if n := strings.Count(dxf, "\nVERTEX\n"); n != 4 {
+		t.Errorf("%d vertices, want 4", n)
+	}
+}
@@ -0,0 +1,446 @@
+//go:build !cgo || purego
+// +build !cgo purego
+
+package choppy
+
+import "math"
+
+// triangulates the exterior and interiors by ear clipping, returning the
+// points and counterclockwise triangles indexing them; unlike the triangle
+// library no points are added, and collinear boundary points are kept so
+// the cap shares every vertex with the clipped walls
+func triangulate(paths []Path) ([][2]float64, [][3]int32) {
+	if len(paths[0]) < 3 {
+		return nil, nil
+	}
+	var points [][2]float64
+	var rings [][2]int
+	for _, path := range paths {
+		start := len(points)
+		for _, p := range path {
+			points = append(points, [2]float64{p.X, p.Y})
+		}
+		rings = append(rings, [2]int{start, len(points)})
+	}
+	var triangles [][3]int32
+	for _, t := range earcut(points, rings) {
+		triangles = append(triangles, [3]int32{int32(t[0]), int32(t[1]), int32(t[2])})
+	}
+	return points, triangles
+}
+
+// port of mapbox/earcut using the exact orientation predicate
+
+type earNode struct {
+	i          int
+	x, y       float64
+	prev, next *earNode
+	steiner    bool
+}
+
+// negative for a counterclockwise turn p, q, r
+func earArea(p, q, r *earNode) float64 {
+	return -orient2d(p.x, p.y, q.x, q.y, r.x, r.y)
+}
+
+func earEquals(a, b *earNode) bool {
+	return a.x == b.x && a.y == b.y
+}
+
+func earSignedArea(points [][2]float64, start, end int) float64 {
+	var sum float64
+	j := end - 1
+	for i := start; i < end; i++ {
+		sum += (points[j][0] - points[i][0]) * (points[i][1] + points[j][1])
+		j = i
+	}
+	return sum
+}
+
+func earInsert(i int, x, y float64, last *earNode) *earNode {
+	p := &earNode{i: i, x: x, y: y}
+	if last == nil {
+		p.prev = p
+		p.next = p
+	} else {
+		p.next = last.next
+		p.prev = last
+		last.next.prev = p
+		last.next = p
+	}
+	return p
+}
+
+func earRemove(p *earNode) {
+	p.next.prev = p.prev
+	p.prev.next = p.next
+}
+
+// circular list of a ring, counterclockwise for the exterior and clockwise
+// for holes
+func earLinkedList(points [][2]float64, start, end int, ccw bool) *earNode {
+	var last *earNode
+	if ccw == (earSignedArea(points, start, end) > 0) {
+		for i := start; i < end; i++ {
+			last = earInsert(i, points[i][0], points[i][1], last)
+		}
+	} else {
+		for i := end - 1; i >= start; i-- {
+			last = earInsert(i, points[i][0], points[i][1], last)
+		}
+	}
+	if last != nil && earEquals(last, last.next) {
+		earRemove(last)
+		last = last.next
+	}
+	return last
+}
+
+// removes duplicate points, and collinear points too when collinear is set;
+// that would leave boundary vertices out of the cap so it is a last resort
+func earFilter(start, end *earNode, collinear bool) *earNode {
+	if start == nil {
+		return start
+	}
+	if end == nil {
+		end = start
+	}
+	p := start
+	for {
+		again := false
+		if !p.steiner && (earEquals(p, p.next) || collinear && earArea(p.prev, p, p.next) == 0) {
+			earRemove(p)
+			p = p.prev
+			end = p
+			if p == p.next {
+				break
+			}
+			again = true
+		} else {
+			p = p.next
+		}
+		if !again && p == end {
+			break
+		}
+	}
+	return end
+}
+
+func earcutLinked(ear *earNode, triangles [][3]int, pass int) [][3]int {
+	if ear == nil {
+		return triangles
+	}
+	stop := ear
+	for ear.prev != ear.next {
+		prev := ear.prev
+		next := ear.next
+		if isEar(ear) {
+			triangles = append(triangles, [3]int{prev.i, ear.i, next.i})
+			earRemove(ear)
+			ear = next.next
+			stop = next.next
+			continue
+		}
+		ear = next
+		if ear == stop {
+			switch pass {
+			case 0:
+				triangles = earcutLinked(earFilter(ear, nil, true), triangles, 1)
+			case 1:
+				ear, triangles = cureLocalIntersections(earFilter(ear, nil, true), triangles)
+				triangles = earcutLinked(ear, triangles, 2)
+			case 2:
+				triangles = splitEarcut(ear, triangles)
+			}
+			break
+		}
+	}
+	return triangles
+}
+
+func isEar(ear *earNode) bool {
+	a := ear.prev
+	b := ear
+	c := ear.next
+	if earArea(a, b, c) >= 0 {
+		return false
+	}
+	for p := c.next; p != a; p = p.next {
+		if !earEquals(p, a) && pointInTriangle(a.x, a.y, b.x, b.y, c.x, c.y, p.x, p.y) &&
+			earArea(p.prev, p, p.next) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func cureLocalIntersections(start *earNode, triangles [][3]int) (*earNode, [][3]int) {
+	p := start
+	for {
+		a := p.prev
+		b := p.next.next
+		if !earEquals(a, b) && earIntersects(a, p, p.next, b) && locallyInside(a, b) && locallyInside(b, a) {
+			triangles = append(triangles, [3]int{a.i, p.i, b.i})
+			earRemove(p)
+			earRemove(p.next)
+			p = b
+			start = b
+		}
+		p = p.next
+		if p == start {
+			break
+		}
+	}
+	return earFilter(p, nil, true), triangles
+}
+
+func splitEarcut(start *earNode, triangles [][3]int) [][3]int {
+	a := start
+	for {
+		for b := a.next.next; b != a.prev; b = b.next {
+			if a.i != b.i && isValidDiagonal(a, b) {
+				c := splitPolygon(a, b)
+				a = earFilter(a, a.next, false)
+				c = earFilter(c, c.next, false)
+				triangles = earcutLinked(a, triangles, 0)
+				triangles = earcutLinked(c, triangles, 0)
+				return triangles
+			}
+		}
+		a = a.next
+		if a == start {
+			break
+		}
+	}
+	return triangles
+}
+
+func eliminateHoles(points [][2]float64, holes [][2]int, outer *earNode) *earNode {
+	var queue []*earNode
+	for _, h := range holes {
+		list := earLinkedList(points, h[0], h[1], false)
+		if list == nil {
+			continue
+		}
+		if list == list.next {
+			list.steiner = true
+		}
+		queue = append(queue, leftmost(list))
+	}
+	// bridge holes from left to right
+	for i := 1; i < len(queue); i++ {
+		for j := i; j > 0 && (queue[j].x < queue[j-1].x || (queue[j].x == queue[j-1].x && queue[j].y < queue[j-1].y)); j-- {
+			queue[j], queue[j-1] = queue[j-1], queue[j]
+		}
+	}
+	for _, hole := range queue {
+		outer = eliminateHole(hole, outer)
+	}
+	return outer
+}
+
+func eliminateHole(hole, outer *earNode) *earNode {
+	bridge := findHoleBridge(hole, outer)
+	if bridge == nil {
+		return outer
+	}
+	bridgeReverse := splitPolygon(bridge, hole)
+	earFilter(bridgeReverse, bridgeReverse.next, false)
+	return earFilter(bridge, bridge.next, false)
+}
+
+// finds a point on the outer ring visible from the leftmost point of a hole
+func findHoleBridge(hole, outer *earNode) *earNode {
+	p := outer
+	hx := hole.x
+	hy := hole.y
+	qx := math.Inf(-1)
+	var m *earNode
+	for {
+		if hy <= p.y && hy >= p.next.y && p.next.y != p.y {
+			x := p.x + (hy-p.y)*(p.next.x-p.x)/(p.next.y-p.y)
+			if x <= hx && x > qx {
+				qx = x
+				if p.next.x > p.x {
+					m = p
+				} else {
+					m = p.next
+				}
+				if x == hx {
+					return m
+				}
+			}
+		}
+		p = p.next
+		if p == outer {
+			break
+		}
+	}
+	if m == nil {
+		return nil
+	}
+	stop := m
+	mx := m.x
+	my := m.y
+	tanMin := math.Inf(1)
+	p = m
+	for {
+		ax, cx := qx, hx
+		if hy < my {
+			ax, cx = hx, qx
+		}
+		if hx >= p.x && p.x >= mx && hx != p.x && pointInTriangle(ax, hy, mx, my, cx, hy, p.x, p.y) {
+			tan := math.Abs(hy-p.y) / (hx - p.x)
+			if locallyInside(p, hole) && (tan < tanMin || (tan == tanMin && (p.x > m.x || (p.x == m.x && sectorContainsSector(m, p))))) {
+				m = p
+				tanMin = tan
+			}
+		}
+		p = p.next
+		if p == stop {
+			break
+		}
+	}
+	return m
+}
+
+func sectorContainsSector(m, p *earNode) bool {
+	return earArea(m.prev, m, p.prev) < 0 && earArea(p.next, m, m.next) < 0
+}
+
+func leftmost(start *earNode) *earNode {
+	p := start
+	result := start
+	for {
+		if p.x < result.x || (p.x == result.x && p.y < result.y) {
+			result = p
+		}
+		p = p.next
+		if p == start {
+			break
+		}
+	}
+	return result
+}
+
+// reports whether p is inside or on the counterclockwise triangle a, b, c
+func pointInTriangle(ax, ay, bx, by, cx, cy, px, py float64) bool {
+	return orient2d(cx, cy, ax, ay, px, py) >= 0 &&
+		orient2d(ax, ay, bx, by, px, py) >= 0 &&
+		orient2d(bx, by, cx, cy, px, py) >= 0
+}
+
+func isValidDiagonal(a, b *earNode) bool {
+	return a.next.i != b.i && a.prev.i != b.i && !intersectsPolygon(a, b) &&
+		(locallyInside(a, b) && locallyInside(b, a) && middleInside(a, b) &&
+			(earArea(a.prev, a, b.prev) != 0 || earArea(a, b.prev, b) != 0) ||
+			earEquals(a, b) && earArea(a.prev, a, a.next) > 0 && earArea(b.prev, b, b.next) > 0)
+}
+
+func earSign(x float64) int {
+	if x > 0 {
+		return 1
+	}
+	if x < 0 {
+		return -1
+	}
+	return 0
+}
+
+// reports whether q lies in the bounding box of the collinear p and r
+func onSegment(p, q, r *earNode) bool {
+	return q.x <= math.Max(p.x, r.x) && q.x >= math.Min(p.x, r.x) &&
+		q.y <= math.Max(p.y, r.y) && q.y >= math.Min(p.y, r.y)
+}
+
+func earIntersects(p1, q1, p2, q2 *earNode) bool {
+	o1 := earSign(earArea(p1, q1, p2))
+	o2 := earSign(earArea(p1, q1, q2))
+	o3 := earSign(earArea(p2, q2, p1))
+	o4 := earSign(earArea(p2, q2, q1))
+	if o1 != o2 && o3 != o4 {
+		return true
+	}
+	if o1 == 0 && onSegment(p1, p2, q1) {
+		return true
+	}
+	if o2 == 0 && onSegment(p1, q2, q1) {
+		return true
+	}
+	if o3 == 0 && onSegment(p2, p1, q2) {
+		return true
+	}
+	if o4 == 0 && onSegment(p2, q1, q2) {
+		return true
+	}
+	return false
+}
+
+func intersectsPolygon(a, b *earNode) bool {
+	p := a
+	for {
+		if p.i != a.i && p.next.i != a.i && p.i != b.i && p.next.i != b.i && earIntersects(p, p.next, a, b) {
+			return true
+		}
+		p = p.next
+		if p == a {
+			break
+		}
+	}
+	return false
+}
+
+func locallyInside(a, b *earNode) bool {
+	if earArea(a.prev, a, a.next) < 0 {
+		return earArea(a, b, a.next) >= 0 && earArea(a, a.prev, b) >= 0
+	}
+	return earArea(a, b, a.prev) < 0 || earArea(a, a.next, b) < 0
+}
+
+func middleInside(a, b *earNode) bool {
+	p := a
+	inside := false
+	px := (a.x + b.x) / 2
+	py := (a.y + b.y) / 2
+	for {
+		if (p.y > py) != (p.next.y > py) && p.next.y != p.y &&
+			px < (p.next.x-p.x)*(py-p.y)/(p.next.y-p.y)+p.x {
+			inside = !inside
+		}
+		p = p.next
+		if p == a {
+			break
+		}
+	}
+	return inside
+}
+
+// links a to b with a diagonal, splitting the ring in two; returns the
+// copy of b on the other ring
+func splitPolygon(a, b *earNode) *earNode {
+	a2 := &earNode{i: a.i, x: a.x, y: a.y}
+	b2 := &earNode{i: b.i, x: b.x, y: b.y}
+	an := a.next
+	bp := b.prev
+	a.next = b
+	b.prev = a
+	a2.next = an
+	an.prev = a2
+	b2.next = a2
+	a2.prev = b2
+	bp.next = b2
+	b2.prev = bp
+	return b2
+}
+
+// rings[0] is the exterior and the rest are holes, each [start, end) in points
+func earcut(points [][2]float64, rings [][2]int) [][3]int {
+	outer := earLinkedList(points, rings[0][0], rings[0][1], true)
+	if outer == nil || outer.next == outer.prev {
+		return nil
+	}
+	if len(rings) > 1 {
+		outer = eliminateHoles(points, rings[1:], outer)
+	}
+	return earcutLinked(outer, nil, 0)
+}
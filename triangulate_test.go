@@ -0,0 +1,48 @@
+package choppy
+
+import (
+	"math"
+	"testing"
+
+	"github.com/fogleman/fauxgl"
+)
+
+func square(x0, y0, x1, y1 float64) Path {
+	return Path{{x0, y0, 0}, {x1, y0, 0}, {x1, y1, 0}, {x0, y1, 0}}
+}
+
+func TestTriangulate(t *testing.T) {
+	cases := []struct {
+		name  string
+		paths []Path
+		area  float64
+	}{
+		{"square", []Path{square(0, 0, 10, 10)}, 100},
+		{"collinear points", []Path{{{0, 0, 0}, {5, 0, 0}, {10, 0, 0}, {10, 5, 0}, {10, 10, 0}, {0, 10, 0}}}, 100},
+		{"comb", []Path{{{0, 0, 0}, {10, 0, 0}, {10, 10, 0}, {8, 10, 0}, {8, 2, 0}, {6, 2, 0}, {6, 10, 0},
+			{4, 10, 0}, {4, 2, 0}, {2, 2, 0}, {2, 10, 0}, {0, 10, 0}}}, 100 - 2*16},
+		{"hole", []Path{square(0, 0, 10, 10), reversePath(square(2, 2, 8, 8))}, 64},
+		{"two holes", []Path{square(0, 0, 10, 10), reversePath(square(1, 1, 4, 9)), reversePath(square(6, 1, 9, 9))}, 100 - 2*24},
+	}
+	for _, c := range cases {
+		points, triangles := triangulate(c.paths)
+		var area float64
+		for _, tri := range triangles {
+			var p [3]fauxgl.Vector
+			for k, i := range tri {
+				if int(i) >= len(points) {
+					t.Fatalf("%s: index %d of %d points", c.name, i, len(points))
+				}
+				p[k] = fauxgl.Vector{points[i][0], points[i][1], 0}
+			}
+			a := p[1].Sub(p[0]).Cross(p[2].Sub(p[0])).Z / 2
+			if a < 0 {
+				t.Errorf("%s: clockwise triangle %v", c.name, p)
+			}
+			area += a
+		}
+		if math.Abs(area-c.area) > 1e-9 {
+			t.Errorf("%s: area %g, want %g", c.name, area, c.area)
+		}
+	}
+}
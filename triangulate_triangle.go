@@ -0,0 +1,52 @@
+//go:build cgo && !purego
+// +build cgo,!purego
+
+package choppy
+
+import "github.com/fogleman/triangle"
+
+// triangulates the exterior and interiors with the cgo triangle library,
+// returning the points and counterclockwise triangles indexing them
+func triangulate(paths []Path) ([][2]float64, [][3]int32) {
+	var points [][2]float64
+	var segments [][2]int32
+	for _, path := range paths {
+		// path = path[1:]
+		start := len(points)
+		for i, p := range path {
+			points = append(points, [2]float64{p.X, p.Y})
+			i1 := len(points) - 1
+			i2 := i1 + 1
+			if i == len(path)-1 {
+				i2 = start
+			}
+			segments = append(segments, [2]int32{int32(i1), int32(i2)})
+		}
+	}
+
+	var holes [][2]float64
+	for _, hole := range paths[1:] {
+		p, _ := hole.HolePoint()
+		holes = append(holes, [2]float64{p.X, p.Y})
+	}
+
+	if len(segments) < 3 {
+		return nil, nil
+	}
+
+	in := triangle.NewTriangulateIO()
+	in.SetPoints(points)
+	in.SetSegments(segments)
+	if len(holes) > 0 {
+		in.SetHoles(holes)
+	}
+	opts := triangle.NewOptions()
+	opts.ConformingDelaunay = false
+	opts.SegmentSplitting = triangle.NoSplitting
+	out := triangle.Triangulate(in, opts, false)
+	points = out.Points()
+	triangles := out.Triangles()
+	triangle.FreeTriangulateIO(in)
+	triangle.FreeTriangulateIO(out)
+	return points, triangles
+}
@@ -1,33 +1,44 @@
 package choppy
 
-import "math"
+import (
+	"math"
 
+	"github.com/fogleman/fauxgl"
+)
+
+// whether the segments cross at a single point inside both of them
 func segmentsIntersect(v1x1, v1y1, v1x2, v1y2, v2x1, v2y1, v2x2, v2y2 float64) bool {
-	const eps = 1e-9
-	a1 := v1y2 - v1y1
-	b1 := v1x1 - v1x2
-	c1 := (v1x2 * v1y1) - (v1x1 * v1y2)
-	d1 := (a1 * v2x1) + (b1 * v2y1) + c1
-	d2 := (a1 * v2x2) + (b1 * v2y2) + c1
-	if d1 > -eps && d2 > -eps {
-		return false
-	}
-	if d1 < eps && d2 < eps {
+	d1 := orient2d(v1x1, v1y1, v1x2, v1y2, v2x1, v2y1)
+	d2 := orient2d(v1x1, v1y1, v1x2, v1y2, v2x2, v2y2)
+	if d1 >= 0 && d2 >= 0 || d1 <= 0 && d2 <= 0 {
 		return false
 	}
-	a2 := v2y2 - v2y1
-	b2 := v2x1 - v2x2
-	c2 := (v2x2 * v2y1) - (v2x1 * v2y2)
-	d1 = (a2 * v1x1) + (b2 * v1y1) + c2
-	d2 = (a2 * v1x2) + (b2 * v1y2) + c2
-	if d1 > -eps && d2 > -eps {
+	d1 = orient2d(v2x1, v2y1, v2x2, v2y2, v1x1, v1y1)
+	d2 = orient2d(v2x1, v2y1, v2x2, v2y2, v1x2, v1y2)
+	if d1 >= 0 && d2 >= 0 || d1 <= 0 && d2 <= 0 {
 		return false
 	}
-	if d1 < eps && d2 < eps {
-		return false
+	return true
+}
+
+func segmentDistance(p, v, w fauxgl.Vector) float64 {
+	return p.Distance(segmentPoint(p, v, w))
+}
+
+// point of the segment from v to w nearest p
+func segmentPoint(p, v, w fauxgl.Vector) fauxgl.Vector {
+	l2 := v.DistanceSquared(w)
+	if l2 == 0 {
+		return v
 	}
-	if math.Abs((a1*b2)-(a2*b1)) < eps {
-		return false
+	t := p.Sub(v).Dot(w.Sub(v)) / l2
+	t = math.Max(0, math.Min(1, t))
+	return v.Add(w.Sub(v).MulScalar(t))
+}
+
+func newTriangleFacing(p1, p2, p3, normal fauxgl.Vector) *fauxgl.Triangle {
+	if p2.Sub(p1).Cross(p3.Sub(p1)).Dot(normal) < 0 {
+		p2, p3 = p3, p2
 	}
-	return true
+	return fauxgl.NewTriangleForPoints(p1, p2, p3)
 }
@@ -0,0 +1,231 @@
+package choppy
+
+import (
+	"math"
+	"sort"
+
+	"github.com/fogleman/fauxgl"
+)
+
+type edge struct {
+	A, B int
+}
+
+// distance below which points of the mesh are considered the same
+func defaultTolerance(mesh *fauxgl.Mesh) float64 {
+	return mesh.BoundingBox().Size().Length() * 1e-9
+}
+
+// indexes the mesh vertices, merging vertices closer than distance into the
+// first one seen, and counts the positions that moved
+func weldVertices(mesh *fauxgl.Mesh, distance float64) ([]fauxgl.Vector, [][3]int, int) {
+	w := newWelder(distance)
+	faces := make([][3]int, len(mesh.Triangles))
+	for i, t := range mesh.Triangles {
+		faces[i] = [3]int{w.index(t.V1.Position), w.index(t.V2.Position), w.index(t.V3.Position)}
+	}
+	return w.points, faces, w.welded
+}
+
+type cell struct {
+	X, Y, Z int
+}
+
+type welder struct {
+	distance float64
+	points   []fauxgl.Vector
+	grid     map[cell][]int
+	lookup   map[fauxgl.Vector]int
+	welded   int
+}
+
+func newWelder(distance float64) *welder {
+	grid := make(map[cell][]int)
+	lookup := make(map[fauxgl.Vector]int)
+	return &welder{distance, nil, grid, lookup, 0}
+}
+
+func (w *welder) cell(p fauxgl.Vector) cell {
+	return cell{
+		int(math.Floor(p.X / w.distance)),
+		int(math.Floor(p.Y / w.distance)),
+		int(math.Floor(p.Z / w.distance))}
+}
+
+// index of the first point seen within distance of p
+func (w *welder) index(p fauxgl.Vector) int {
+	if i, ok := w.lookup[p]; ok {
+		return i
+	}
+	k := w.cell(p)
+	for dx := -1; dx <= 1; dx++ {
+		for dy := -1; dy <= 1; dy++ {
+			for dz := -1; dz <= 1; dz++ {
+				for _, i := range w.grid[cell{k.X + dx, k.Y + dy, k.Z + dz}] {
+					if w.points[i].Distance(p) <= w.distance {
+						w.lookup[p] = i
+						w.welded++
+						return i
+					}
+				}
+			}
+		}
+	}
+	i := len(w.points)
+	w.points = append(w.points, p)
+	w.grid[k] = append(w.grid[k], i)
+	w.lookup[p] = i
+	return i
+}
+
+func (w *welder) snap(p fauxgl.Vector) fauxgl.Vector {
+	return w.points[w.index(p)]
+}
+
+// adds p as a point of its own, even within distance of another
+func (w *welder) add(p fauxgl.Vector) {
+	if _, ok := w.lookup[p]; ok {
+		return
+	}
+	k := w.cell(p)
+	i := len(w.points)
+	w.points = append(w.points, p)
+	w.grid[k] = append(w.grid[k], i)
+	w.lookup[p] = i
+}
+
+// nearest point within distance of p, without adding p
+func (w *welder) nearest(p fauxgl.Vector) (fauxgl.Vector, bool) {
+	if i, ok := w.lookup[p]; ok {
+		return w.points[i], true
+	}
+	best := -1
+	var bestDistance float64
+	k := w.cell(p)
+	for dx := -1; dx <= 1; dx++ {
+		for dy := -1; dy <= 1; dy++ {
+			for dz := -1; dz <= 1; dz++ {
+				for _, i := range w.grid[cell{k.X + dx, k.Y + dy, k.Z + dz}] {
+					d := w.points[i].Distance(p)
+					if d <= w.distance && (best < 0 || d < bestDistance) {
+						best = i
+						bestDistance = d
+					}
+				}
+			}
+		}
+	}
+	if best < 0 {
+		return p, false
+	}
+	return w.points[best], true
+}
+
+// splits faces whose boundary edges pass within distance of another
+// boundary vertex, so that the vertex becomes shared
+func splitTJunctions(vertices []fauxgl.Vector, faces [][3]int, sources []int, distance float64) ([][3]int, []int) {
+	m := NewIndexedMesh(vertices, faces)
+	var candidates []int
+	seen := make(map[int]bool)
+	for _, e := range m.HalfEdges {
+		if e.Twin < 0 && !seen[e.Origin] {
+			seen[e.Origin] = true
+			candidates = append(candidates, e.Origin)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return vertices[candidates[i]].X < vertices[candidates[j]].X
+	})
+
+	// vertices to insert along each split half-edge, ordered from its origin
+	splits := make(map[int][]int)
+	for h, e := range m.HalfEdges {
+		if e.Twin >= 0 {
+			continue
+		}
+		a := vertices[e.Origin]
+		b := vertices[m.Dest(h)]
+		x0 := math.Min(a.X, b.X) - distance
+		x1 := math.Max(a.X, b.X) + distance
+		i := sort.Search(len(candidates), func(i int) bool {
+			return vertices[candidates[i]].X >= x0
+		})
+		var points []int
+		var ts []float64
+		for ; i < len(candidates) && vertices[candidates[i]].X <= x1; i++ {
+			v := candidates[i]
+			if v == e.Origin || v == m.Dest(h) {
+				continue
+			}
+			p := vertices[v]
+			if segmentDistance(p, a, b) > distance {
+				continue
+			}
+			t := p.Sub(a).Dot(b.Sub(a)) / b.Sub(a).LengthSquared()
+			if t <= 0 || t >= 1 {
+				continue
+			}
+			points = append(points, v)
+			ts = append(ts, t)
+		}
+		if len(points) == 0 {
+			continue
+		}
+		sort.Sort(byParameter{points, ts})
+		splits[h] = points
+	}
+	if len(splits) == 0 {
+		return faces, sources
+	}
+
+	result := make([][3]int, 0, len(faces)+len(splits))
+	resultSources := make([]int, 0, len(faces)+len(splits))
+	for f, face := range faces {
+		var points [3][]int
+		for j := 0; j < 3; j++ {
+			points[j] = splits[3*f+j]
+		}
+		for _, g := range splitFace(face, points) {
+			result = append(result, g)
+			resultSources = append(resultSources, sources[f])
+		}
+	}
+	return result, resultSources
+}
+
+// splits a face at points along its edges, where points[j] lie on the edge
+// from f[j] to f[j+1]
+func splitFace(f [3]int, points [3][]int) [][3]int {
+	for j := 0; j < 3; j++ {
+		if len(points[j]) == 0 {
+			continue
+		}
+		a, b, c := f[j], f[(j+1)%3], f[(j+2)%3]
+		ring := append(append([]int{a}, points[j]...), b)
+		// fan from the opposite corner; the first and last triangles keep
+		// the other two edges
+		var result [][3]int
+		n := len(ring) - 1
+		result = append(result, splitFace(
+			[3]int{c, a, ring[1]}, [3][]int{points[(j+2)%3], nil, nil})...)
+		for k := 1; k < n-1; k++ {
+			result = append(result, [3]int{c, ring[k], ring[k+1]})
+		}
+		result = append(result, splitFace(
+			[3]int{c, ring[n-1], b}, [3][]int{nil, nil, points[(j+1)%3]})...)
+		return result
+	}
+	return [][3]int{f}
+}
+
+type byParameter struct {
+	points []int
+	ts     []float64
+}
+
+func (a byParameter) Len() int           { return len(a.points) }
+func (a byParameter) Less(i, j int) bool { return a.ts[i] < a.ts[j] }
+func (a byParameter) Swap(i, j int) {
+	a.points[i], a.points[j] = a.points[j], a.points[i]
+	a.ts[i], a.ts[j] = a.ts[j], a.ts[i]
+}